@@ -0,0 +1,66 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"strings"
+)
+
+//Errors returned by AliasHost method.
+var (
+	//ErrHostAliasMustBeValid is returned by AliasHost method when a host is empty, both hosts are equal, or the aliases would be chained.
+	ErrHostAliasMustBeValid = errors.New("mux: invalid host alias")
+)
+
+//AliasHost makes requests to the alias host be matched against the routes of the canonical host, without redirects.
+//Eg: m.AliasHost("www.example.com", "example.com") .
+//
+//Hosts are compared case-insensitively, including the port. Aliases are not chained, and an existing alias is replaced.
+//
+//Errors
+//
+//• mux.ErrHostAliasMustBeValid
+func (m *Mux) AliasHost(alias, canonical string) error {
+	alias, canonical = strings.ToLower(alias), strings.ToLower(canonical)
+	if alias == "" || canonical == "" || alias == canonical {
+		return ErrHostAliasMustBeValid
+	}
+	m.entriesLock.Lock()
+	defer m.entriesLock.Unlock()
+	if _, isAlias := m.hostAliases[canonical]; isAlias {
+		return ErrHostAliasMustBeValid
+	}
+	for _, c := range m.hostAliases {
+		if c == alias {
+			return ErrHostAliasMustBeValid
+		}
+	}
+	if m.hostAliases == nil {
+		m.hostAliases = map[string]string{}
+	}
+	m.hostAliases[alias] = canonical
+	return nil
+}
+
+//routingHost returns the canonical (lowercase) host of a request host. The caller must hold entriesLock.
+func (m *Mux) routingHost(host string) string {
+	host = strings.ToLower(host)
+	if canonical, isAlias := m.hostAliases[host]; isAlias {
+		return canonical
+	}
+	return host
+}
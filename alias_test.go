@@ -0,0 +1,57 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_AliasHost_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://example.com/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx, _ := mux.Get(r)
+		fmt.Fprint(w, r.Host, " ", mx.PathVars(r)["id"])
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AliasHost("www.example.com", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://www.example.com/users/42", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "www.example.com 42", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_AliasHost_failHostAliasMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.AliasHost("example.com", "example.com"); err != mux.ErrHostAliasMustBeValid {
+		t.Fatal("expected: mux.ErrHostAliasMustBeValid")
+	}
+	if err := m.AliasHost("www.example.com", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AliasHost("w3.example.com", "www.example.com"); err != mux.ErrHostAliasMustBeValid {
+		t.Fatal("expected: mux.ErrHostAliasMustBeValid")
+	}
+}
@@ -0,0 +1,178 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+//HTTP methods that change server state, and must be protected against method spoofing and CSRF.
+var unsafeHTTPMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+//Protected declares the mechanisms (Eg: "csrf", "session", "api-key") protecting a route against forged requests.
+//
+//The mechanisms are not enforced by the Mux itself. They are metadata reported by RouteInfo and used by UnprotectedRoutes method, so security reviews can be automated.
+func Protected(mechanisms ...string) RouteOption {
+	return func(o *routeOptions) error {
+		o.protections = append(o.protections, mechanisms...)
+		return nil
+	}
+}
+
+//UnprotectedRoutes returns the routes reachable through unsafe methods (POST, PUT, PATCH, DELETE and extension methods) without any mechanism declared by the Protected option, in routing table order.
+func (m *Mux) UnprotectedRoutes() []RouteInfo {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	routes := []RouteInfo{}
+	for _, e := range m.entries {
+		if !isUnsafeMethod(e.route.method) || len(e.options.protections) > 0 {
+			continue
+		}
+		routes = append(routes, newRouteInfo(e))
+	}
+	return routes
+}
+
+//UnusedRoutes returns the routes that were never dispatched (zero hits), in routing table order, so dead routes can be cleaned up.
+//
+//Hits are only counted when Mux.AuditHits is set.
+func (m *Mux) UnusedRoutes() []RouteInfo {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	routes := []RouteInfo{}
+	for _, e := range m.entries {
+		if atomic.LoadUint64(&e.stats.hits) > 0 {
+			continue
+		}
+		routes = append(routes, newRouteInfo(e))
+	}
+	return routes
+}
+
+//Unreachable returns the routes fully shadowed by routes tested before them, in routing table order, so misconfigurations are detectable programmatically.
+//
+//Conflicting routes are rejected by Handle method, so only routes differing by matchers can be shadowed.
+//Eg: A route with mux.Countries("BR") is unreachable when a route with mux.Countries("AR", "BR") and the same pattern is tested first.
+//A route is reported only when shadowing can be proven: regular expressions (Eg: in mux.UserAgent) are compared by their text.
+func (m *Mux) Unreachable() []RouteInfo {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	routes := []RouteInfo{}
+	for i, e := range m.entries {
+		for _, before := range m.entries[:i] {
+			if before.route.covers(e.route) {
+				routes = append(routes, newRouteInfo(e))
+				break
+			}
+		}
+	}
+	return routes
+}
+
+//covers tests if the route accepts every request accepted by the other route.
+func (r *muxRoute) covers(other *muxRoute) bool {
+	if r.scheme != other.scheme || r.host != other.host || r.method != other.method {
+		return false
+	}
+
+	//The path must be the same or more general...
+	if !pathCovers(r.path, other.path) {
+		return false
+	}
+
+	//...every query test must be made by the other route too...
+	for _, e := range r.query {
+		if !other.query.implies(e) {
+			return false
+		}
+	}
+
+	//...and the matchers must be less restrictive.
+	if r.protoMajor != 0 && r.protoMajor != other.protoMajor {
+		return false
+	}
+	if len(r.countries) > 0 && (len(other.countries) == 0 || !containsStrings(r.countries, other.countries)) {
+		return false
+	}
+	if !containsStrings(other.exceptCountries, r.exceptCountries) {
+		return false
+	}
+	if r.userAgent != nil && r.userAgent.String() != other.userAgent.String() {
+		return false
+	}
+	if len(r.contentTypes) > 0 && (len(other.contentTypes) == 0 || !mediaTypesCover(r.contentTypes, other.contentTypes)) {
+		return false
+	}
+	return r.flag == "" || r.flag == other.flag
+}
+
+//pathCovers tests if a path pattern matches every path matched by the other path pattern.
+func pathCovers(path, other []string) bool {
+	for i, seg := range path {
+		//A sub path matches any other path with at least one more segment...
+		if seg == "{*}" && i == len(path)-1 {
+			return len(other) > i
+		}
+		//...but a shorter path or another sub path are not covered.
+		if i >= len(other) || (other[i] == "{*}" && i == len(other)-1) {
+			return false
+		}
+		//A variable segment matches any other segment, but a static one only itself.
+		if _, isVar := pathVarName(seg); isVar {
+			continue
+		}
+		if seg != other[i] {
+			return false
+		}
+	}
+	return len(path) == len(other)
+}
+
+//implies tests if every request accepted by the query route is accepted by the query test too.
+func (route queryRoute) implies(test queryEntry) bool {
+	for _, e := range route {
+		if e.Name != test.Name {
+			continue
+		}
+		switch {
+		case test.absent || e.absent:
+			if test.absent == e.absent {
+				return true
+			}
+			continue
+		case test.Value == "":
+			return true
+		case e.Value == "":
+			continue
+		case e.Value == test.Value:
+			return true
+		case e.constraint == nil && test.constraint != nil && test.constraint.match(e.Value):
+			return true
+		}
+	}
+	return false
+}
+
+//containsStrings tests if all the values are in the slice.
+func containsStrings(stringSlice []string, values []string) bool {
+	for _, v := range values {
+		if !containsString(stringSlice, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	//Used in request contexts.
+	ctxAuditPrincipalValue = "gitlab.com/gopherburrow/mux AuditPrincipal"
+)
+
+//Errors returned by the Audited option.
+var (
+	//ErrAuditorMustBeNotNil is returned by Handle method when the Audited option receives a nil Auditor.
+	ErrAuditorMustBeNotNil = errors.New("mux: Auditor must be not nil")
+)
+
+//The key used to store the principal of an audited request, set by SetAuditPrincipal function.
+var ctxAuditPrincipal = ctxType(ctxAuditPrincipalValue)
+
+//Auditor receives the audit events of the routes it is attached to by the Audited option. Eg: writing them to a compliance log.
+//
+//Audit is called concurrently, after the response is written.
+type Auditor interface {
+	Audit(e AuditEvent)
+}
+
+//AuditEvent describes a request served by an audited route.
+type AuditEvent struct {
+	//Time is when the request was received.
+	Time time.Time
+	//Principal identifies who made the request: the value set by SetAuditPrincipal function or, if not set, the common name of the verified client certificate (See `mux.ClientCertVars`).
+	Principal string
+	//Route describes the route that handled the request.
+	Route RouteInfo
+	//Method is the request HTTP method.
+	Method string
+	//URL is the absolute request URL, including scheme and host.
+	URL string
+	//RemoteAddr is the network address of the client.
+	RemoteAddr string
+	//Vars holds the path, host and query variables of the request. See `mux.Mux.PathVars` and `mux.Mux.QueryVars`.
+	Vars map[string]string
+	//Status is the response HTTP status code. Handlers that panic are reported with http.StatusInternalServerError.
+	Status int
+	//Duration is the time spent serving the request.
+	Duration time.Duration
+	//RequestID is the id assigned to the request, when Mux.RequestIDs is set.
+	RequestID string
+}
+
+//Audited makes the route send an AuditEvent for each request to the Auditor, so compliance logging is enforced by the routing configuration instead of by each handler.
+//It is usually set in a Group next to the routes it audits.
+//
+//Errors
+//
+//• mux.ErrAuditorMustBeNotNil
+func Audited(a Auditor) RouteOption {
+	return func(o *routeOptions) error {
+		if a == nil {
+			return ErrAuditorMustBeNotNil
+		}
+		o.auditor = a
+		return nil
+	}
+}
+
+//SetAuditPrincipal sets who is making an audited request (Eg: the authenticated user), reported by AuditEvent.Principal.
+//It is usually called by an authentication middleware or the handler. It does nothing in routes without the Audited option.
+func SetAuditPrincipal(r *http.Request, principal string) {
+	if p, ok := r.Context().Value(ctxAuditPrincipal).(*string); ok {
+		*p = principal
+	}
+}
+
+//audit creates a handler that sends an AuditEvent for each request served by the next one.
+func audit(a Auditor, route RouteInfo, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var principal string
+		//Unverified certificates (Eg: with tls.RequestClientCert) are chosen by the client, so they can not identify it.
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			if cc, err := ClientCertVars(r); err == nil {
+				principal = cc.CommonName
+			}
+		}
+		rw := &responseWriter{ResponseWriter: w}
+		start := time.Now()
+		completed := false
+		defer func() {
+			status := rw.statusCode()
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+			a.Audit(AuditEvent{
+				Time:       start,
+				Principal:  principal,
+				Route:      route,
+				Method:     r.Method,
+				URL:        requestURL(r),
+				RemoteAddr: r.RemoteAddr,
+				Vars:       auditVars(r),
+				Status:     status,
+				Duration:   time.Since(start),
+				RequestID:  RequestID(r),
+			})
+		}()
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), ctxAuditPrincipal, &principal)))
+		completed = true
+	})
+}
+
+//auditVars merges the path and query variables of a request dispatched by a Mux.
+func auditVars(r *http.Request) map[string]string {
+	m, err := Get(r)
+	if err != nil {
+		return map[string]string{}
+	}
+	vars := m.PathVars(r)
+	for k, v := range m.QueryVars(r) {
+		vars[k] = v
+	}
+	return vars
+}
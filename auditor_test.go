@@ -0,0 +1,131 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+//testAuditor keeps the audit events in memory.
+type testAuditor struct {
+	lock   sync.Mutex
+	events []mux.AuditEvent
+}
+
+func (a *testAuditor) Audit(e mux.AuditEvent) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.events = append(a.events, e)
+}
+
+func TestMux_Audited_success(t *testing.T) {
+	auditor := &testAuditor{}
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			mux.SetAuditPrincipal(r, "alice")
+			next.ServeHTTP(w, r)
+		})
+	}
+	m := &mux.Mux{Middleware: []mux.Middleware{authenticate}}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	if err := m.Handle(http.MethodDelete, "http://localhost/accounts/{id}?reason={reason}", h, mux.Audited(auditor)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/accounts/{id}", h); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "http://localhost/accounts/42?reason=fraud", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "http://localhost/accounts/43?reason=test", nil))
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/accounts/42", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(auditor.events) != 2 {
+		t.Fatalf("expected: 2 events, got: %d", len(auditor.events))
+	}
+	e := auditor.events[0]
+	if e.Principal != "alice" || e.Status != http.StatusAccepted || e.Route.URLPattern != "http://localhost/accounts/{id}?reason={reason}" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e.Vars["id"] != "42" || e.Vars["reason"] != "fraud" || e.URL != "http://localhost/accounts/42?reason=fraud" {
+		t.Fatalf("unexpected event vars: %+v", e)
+	}
+	if e := auditor.events[1]; e.Principal != "" || e.Status != http.StatusUnauthorized {
+		t.Fatalf("unexpected rejected event: %+v", e)
+	}
+}
+
+func TestMux_Audited_clientCertAndPanic(t *testing.T) {
+	auditor := &testAuditor{}
+	m := &mux.Mux{}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	if err := m.Handle(http.MethodPost, "https://localhost/transfers", h, mux.Audited(auditor)); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "https://localhost/transfers", nil)
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}}
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected: the handler panic")
+			}
+		}()
+		m.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	if len(auditor.events) != 1 || auditor.events[0].Principal != "billing" || auditor.events[0].Status != http.StatusInternalServerError {
+		t.Fatalf("unexpected events: %+v", auditor.events)
+	}
+}
+
+func TestMux_Audited_successUnverifiedClientCertIgnored(t *testing.T) {
+	auditor := &testAuditor{}
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "https://localhost/transfers", http.HandlerFunc(emptyHandler), mux.Audited(auditor)); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "https://localhost/transfers", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "admin"}}}}
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	if len(auditor.events) != 1 || auditor.events[0].Principal != "" {
+		t.Fatalf("unexpected events: %+v", auditor.events)
+	}
+}
+
+func TestMux_Audited_failAuditorMustBeNotNil(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.Audited(nil)); err != mux.ErrAuditorMustBeNotNil {
+		t.Fatalf("expected: mux.ErrAuditorMustBeNotNil, got: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"net/http"
+)
+
+//RouteSpec holds the Handle method parameters of a route registered by HandleAll method.
+type RouteSpec struct {
+	Method     string
+	URLPattern string
+	Handler    http.Handler
+	Options    []RouteOption
+}
+
+//HandleAll registers many routes at once. Eg: building large routing tables at startup, from generated or configured routes.
+//
+//Every route is validated before the routing table is touched, then the routes are sorted and merged with it once,
+//avoiding the table copy made by each Handle call.
+//
+//The registration is atomic: if any route is invalid or conflicts (with existing routes or with another spec), no route is registered.
+//
+//Errors
+//
+//• Any error returned by Handle method.
+func (m *Mux) HandleAll(specs []RouteSpec) error {
+	return m.replaceAll(nil, specs)
+}
+
+//replaceAll removes the routes of the removals specs (when they exist) and registers the routes of the additions specs, as HandleAll method does, in a single atomic swap.
+//If any route is invalid or conflicts, the routing table is left untouched.
+func (m *Mux) replaceAll(removals, additions []RouteSpec) error {
+	routes := make([]*muxRoute, 0, len(removals))
+	for _, s := range removals {
+		route, err := m.newLookupRoute(s.Method, s.URLPattern, s.Options)
+		if err != nil {
+			return err
+		}
+		routes = append(routes, route)
+	}
+	entries := make([]muxEntry, 0, len(additions))
+	for _, s := range additions {
+		e, err := newHandleEntry(s.Method, s.URLPattern, s.Handler, s.Options)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+	}
+	return m.replaceEntries(routes, entries)
+}
@@ -0,0 +1,103 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_HandleAll_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/b", newTestHandler("b")); err != nil {
+		t.Fatal(err)
+	}
+	specs := make([]mux.RouteSpec, 0, 5000)
+	for i := 4999; i >= 0; i-- {
+		specs = append(specs, mux.RouteSpec{Method: http.MethodGet, URLPattern: fmt.Sprintf("http://localhost/items/%d/{id}", i), Handler: newTestHandler(fmt.Sprint(i))})
+	}
+	specs = append(specs, mux.RouteSpec{Method: http.MethodGet, URLPattern: "http://localhost/a", Handler: newTestHandler("a")})
+	if err := m.HandleAll(specs); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 5002, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+
+	for _, test := range []struct{ path, want string }{{"/a", "a"}, {"/b", "b"}, {"/items/1234/x", "1234"}} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil))
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestMux_HandleAll_failRouteMustNotConflict(t *testing.T) {
+	m := &mux.Mux{}
+	h := http.HandlerFunc(emptyHandler)
+	err := m.HandleAll([]mux.RouteSpec{
+		{Method: http.MethodGet, URLPattern: "http://localhost/users/{id}", Handler: h, Options: []mux.RouteOption{mux.Owner("first")}},
+		{Method: http.MethodGet, URLPattern: "http://localhost/orders", Handler: h},
+		{Method: http.MethodGet, URLPattern: "http://localhost/users/{name}", Handler: h, Options: []mux.RouteOption{mux.Owner("second")}},
+	})
+	var ce *mux.ConflictError
+	if !errors.As(err, &ce) || !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if want, got := "second first", ce.Owner+" "+ce.ExistingOwner; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := 0, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_HandleAll_failRouteMustNotConflictAnyOrder(t *testing.T) {
+	patterns := []string{"http://localhost/items?id=zzz", "http://localhost/items?id={:/^a/}", "http://localhost/items?id=abc"}
+	//Every permutation of the specs must be rejected, even when the conflicting entries are not sorted together.
+	for _, order := range [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}} {
+		m := &mux.Mux{}
+		specs := make([]mux.RouteSpec, 0, len(order))
+		for _, i := range order {
+			specs = append(specs, mux.RouteSpec{Method: http.MethodGet, URLPattern: patterns[i], Handler: http.HandlerFunc(emptyHandler)})
+		}
+		if err := m.HandleAll(specs); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+			t.Fatalf("order=%v, expected: mux.ErrRouteMustNotConflict", order)
+		}
+		if want, got := 0, len(m.Routes()); want != got {
+			t.Fatalf("order=%v, want=%d, got=%d", order, want, got)
+		}
+	}
+}
+
+func TestMux_HandleAll_failHandlerMustBeNotNil(t *testing.T) {
+	m := &mux.Mux{}
+	err := m.HandleAll([]mux.RouteSpec{
+		{Method: http.MethodGet, URLPattern: "http://localhost/a", Handler: http.HandlerFunc(emptyHandler)},
+		{Method: http.MethodGet, URLPattern: "http://localhost/b"},
+	})
+	if err != mux.ErrHandlerMustBeNotNil {
+		t.Fatal("expected: mux.ErrHandlerMustBeNotNil")
+	}
+	if want, got := 0, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
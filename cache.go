@@ -0,0 +1,153 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+//defaultNotFoundCacheTTL is the time requests not found are cached when Mux.NotFoundCacheTTL is zero.
+const defaultNotFoundCacheTTL = time.Minute
+
+//pathRange is the range of the routing table entries matching a request scheme, host and path, before methods are compared.
+type pathRange struct {
+	lo, hi int
+}
+
+//matchCache is a LRU cache of path ranges, keyed by request scheme, host and path segments. Items may expire.
+//
+//Ranges are indexes of the routing table, so the cache is cleared whenever the table changes, holding the entriesLock write lock.
+type matchCache struct {
+	lock  sync.Mutex
+	items map[string]*list.Element
+	order list.List
+}
+
+//cacheItem is an element of the matchCache order list.
+type cacheItem struct {
+	key string
+	pr  pathRange
+	//expires is when the item is no longer valid. The zero time never expires.
+	expires time.Time
+}
+
+//get returns a cached range not expired at now, marking it as recently used.
+func (c *matchCache) get(key string, now time.Time) (pathRange, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el, found := c.items[key]
+	if !found {
+		return pathRange{}, false
+	}
+	item := el.Value.(*cacheItem)
+	if !item.expires.IsZero() && !now.Before(item.expires) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return pathRange{}, false
+	}
+	c.order.MoveToFront(el)
+	return item.pr, true
+}
+
+//put caches a range until expires, evicting the least recently used ones beyond size.
+func (c *matchCache) put(key string, pr pathRange, size int, expires time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.items == nil {
+		c.items = map[string]*list.Element{}
+	}
+	if el, found := c.items[key]; found {
+		item := el.Value.(*cacheItem)
+		item.pr, item.expires = pr, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&cacheItem{key: key, pr: pr, expires: expires})
+	for c.order.Len() > size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheItem).key)
+	}
+}
+
+//clear removes all cached ranges.
+func (c *matchCache) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.items = nil
+	c.order.Init()
+}
+
+//searchPath finds the range of entries matching a request scheme, host and path segments,
+//using the caches when Mux.MatchCacheSize or Mux.NotFoundCacheSize are set. The caller must hold entriesLock.
+func (m *Mux) searchPath(scheme, host string, reqSegs []string) (lo int, hi int, found bool) {
+	searchHost := func(scheme, host string) (int, int, bool) {
+		return searchRange(
+			len(m.entries), func(i int) int {
+				return compareRequestRoute(scheme, host, reqSegs, m.entries[i].route)
+			})
+	}
+	//The request host is searched before its wildcard hosts, each with the request scheme and then with any scheme.
+	//The not found position is the one of the request scheme and host.
+	search := func() (int, int, bool) {
+		lo, hi, found := searchHost(scheme, host)
+		if found {
+			return lo, hi, found
+		}
+		if wlo, whi, wfound := searchHost(anyScheme, host); wfound {
+			return wlo, whi, wfound
+		}
+		for _, wildcard := range wildcardHosts(host, m.PortInsensitive) {
+			for _, s := range []string{scheme, anyScheme} {
+				if wlo, whi, wfound := searchHost(s, wildcard); wfound {
+					return wlo, whi, wfound
+				}
+			}
+		}
+		return lo, hi, found
+	}
+	if m.MatchCacheSize <= 0 && m.NotFoundCacheSize <= 0 {
+		return search()
+	}
+
+	key, now := scheme+"://"+host+"/"+strings.Join(reqSegs, "/"), time.Now()
+	if m.MatchCacheSize > 0 {
+		if pr, cached := m.cache.get(key, now); cached {
+			return pr.lo, pr.hi, true
+		}
+	}
+	if m.NotFoundCacheSize > 0 {
+		if pr, cached := m.notFoundCache.get(key, now); cached {
+			return pr.lo, pr.hi, false
+		}
+	}
+	lo, hi, found = search()
+	switch {
+	case found && m.MatchCacheSize > 0:
+		m.cache.put(key, pathRange{lo, hi}, m.MatchCacheSize, time.Time{})
+	case !found && m.NotFoundCacheSize > 0:
+		ttl := m.NotFoundCacheTTL
+		if ttl <= 0 {
+			ttl = defaultNotFoundCacheTTL
+		}
+		m.notFoundCache.put(key, pathRange{lo, hi}, m.NotFoundCacheSize, now.Add(ttl))
+	}
+	return lo, hi, found
+}
@@ -0,0 +1,128 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_MatchCacheSize_success(t *testing.T) {
+	m := &mux.Mux{MatchCacheSize: 2}
+	serve := func(method, path string) int {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(method, "http://localhost"+path, nil))
+		return rr.Code
+	}
+	for _, path := range []string{"/a", "/b/{id}", "/c"} {
+		if err := m.Handle(http.MethodGet, "http://localhost"+path, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/a", http.StatusOK},
+		{http.MethodGet, "/b/1", http.StatusOK},
+		{http.MethodGet, "/c", http.StatusOK},
+		{http.MethodGet, "/a", http.StatusOK},
+		{http.MethodPost, "/a", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/b/2", http.StatusOK},
+		{http.MethodGet, "/d", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		if got := serve(test.method, test.path); test.want != got {
+			t.Fatalf("%s %s: want=%d, got=%d", test.method, test.path, test.want, got)
+		}
+	}
+
+	//Table changes invalidate the cached ranges.
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/a"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := http.StatusNotFound, serve(http.MethodGet, "/a"); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/0", newTestHandler("0")); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/c", nil))
+	if want, got := "", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := 200, serve(http.MethodGet, "/0"); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_MatchCacheSize_successConcurrent(t *testing.T) {
+	m := &mux.Mux{MatchCacheSize: 4}
+	if err := m.Handle(http.MethodGet, "http://localhost/items/{id}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rr := httptest.NewRecorder()
+				m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/items/"+string(rune('a'+(i+j)%8)), nil))
+				if rr.Code != http.StatusOK {
+					t.Errorf("want=200, got=%d", rr.Code)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestMux_NotFoundCacheSize_success(t *testing.T) {
+	m := &mux.Mux{NotFoundCacheSize: 2, NotFoundCacheTTL: time.Hour}
+	serve := func(path string) int {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		return rr.Code
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/wp-admin", "/.env", "/wp-admin", "/a"} {
+		want := http.StatusNotFound
+		if path == "/a" {
+			want = http.StatusOK
+		}
+		if got := serve(path); want != got {
+			t.Fatalf("%s: want=%d, got=%d", path, want, got)
+		}
+	}
+
+	//Table changes invalidate the cached requests not found.
+	if err := m.Handle(http.MethodGet, "http://localhost/.env", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := http.StatusOK, serve("/.env"); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
@@ -0,0 +1,121 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+//Deprecation describes a route scheduled for removal, set by mux.Deprecated option.
+type Deprecation struct {
+	//Sunset is when the route is expected to be removed. Zero means not scheduled.
+	Sunset time.Time
+	//Replacement is the route (or a link to the documentation) that clients should migrate to.
+	Replacement string
+}
+
+//Deprecated marks a route as deprecated. The deprecation is metadata reported by RouteInfo and the Catalog, so developer portals can warn route consumers.
+func Deprecated(d Deprecation) RouteOption {
+	return func(o *routeOptions) error {
+		o.deprecation = &d
+		return nil
+	}
+}
+
+//ServiceCatalog is a machine-readable description of the routes of a Mux, consumable by service catalogs and developer portals.
+type ServiceCatalog struct {
+	//Name and Version are the Mux APITitle and APIVersion.
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	//Routes are listed in routing table order.
+	Routes []CatalogRoute `json:"routes"`
+}
+
+//CatalogRoute describes a route in a ServiceCatalog.
+type CatalogRoute struct {
+	Method     string `json:"method"`
+	URLPattern string `json:"urlPattern"`
+	//Owner is set by mux.Owner option.
+	Owner   string `json:"owner,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	//Auth lists the mechanisms set by mux.Protected option (mux.SameOrigin is not listed). AuthRequired is false when there is none.
+	Auth         []string `json:"auth,omitempty"`
+	AuthRequired bool     `json:"authRequired"`
+	//Deprecated, Sunset and Replacement are set by mux.Deprecated option.
+	Deprecated  bool       `json:"deprecated"`
+	Sunset      *time.Time `json:"sunset,omitempty"`
+	Replacement string     `json:"replacement,omitempty"`
+}
+
+//Catalog describes the routing table as a ServiceCatalog.
+func (m *Mux) Catalog() ServiceCatalog {
+	name := m.APITitle
+	if name == "" {
+		name = "API"
+	}
+	routes := m.Routes()
+	c := ServiceCatalog{Name: name, Version: m.APIVersion, Routes: make([]CatalogRoute, len(routes))}
+	for i, ri := range routes {
+		c.Routes[i] = CatalogRoute{
+			Method:       ri.Method,
+			URLPattern:   ri.URLPattern,
+			Owner:        ri.Owner,
+			Summary:      ri.Summary,
+		}
+		//SameOrigin is a protection, but not an authentication mechanism.
+		for _, p := range ri.Protections {
+			if p != sameOriginProtection {
+				c.Routes[i].Auth = append(c.Routes[i].Auth, p)
+			}
+		}
+		c.Routes[i].AuthRequired = len(c.Routes[i].Auth) > 0
+		if d := ri.Deprecation; d != nil {
+			c.Routes[i].Deprecated, c.Routes[i].Replacement = true, d.Replacement
+			if !d.Sunset.IsZero() {
+				sunset := d.Sunset
+				c.Routes[i].Sunset = &sunset
+			}
+		}
+	}
+	return c
+}
+
+//ServeCatalog registers a GET route serving the JSON ServiceCatalog generated by Catalog method.
+//
+//The catalog is generated on each request, so it is kept in sync as routes are added or removed at runtime.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+func (m *Mux) ServeCatalog(urlPattern string, opts ...RouteOption) error {
+	handler := m.serveJSON(func() ([]byte, error) {
+		return json.Marshal(m.Catalog())
+	})
+	return m.Handle(http.MethodGet, urlPattern, handler, opts...)
+}
+
+//serveJSON creates a handler serving the JSON document generated on each request, as the ServeCatalog, ServeStats, ServeClientSpec and ServeOpenAPI methods do.
+func (m *Mux) serveJSON(document func() ([]byte, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, err := document()
+		if err != nil {
+			m.errorRenderer().RenderError(w, r, newProblem(r, http.StatusInternalServerError, ""))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(doc)
+	})
+}
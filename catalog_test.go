@@ -0,0 +1,76 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ServeCatalog_success(t *testing.T) {
+	m := &mux.Mux{APITitle: "Orders", APIVersion: "2.1.0"}
+	if err := m.ServeCatalog("http://localhost/catalog.json", mux.Owner("platform")); err != nil {
+		t.Fatal(err)
+	}
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", http.HandlerFunc(emptyHandler),
+		mux.Owner("billing"), mux.Annotate("Create order", ""), mux.Protected("session", "csrf"),
+		mux.Deprecated(mux.Deprecation{Sunset: sunset, Replacement: "POST+http://localhost/v2/orders"})); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/catalog.json", nil))
+	if want, got := "application/json", rr.Header().Get("Content-Type"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := `{"name":"Orders","version":"2.1.0","routes":[`+
+		`{"method":"GET","urlPattern":"http://localhost/catalog.json","owner":"platform","authRequired":false,"deprecated":false},`+
+		`{"method":"POST","urlPattern":"http://localhost/orders","owner":"billing","summary":"Create order","auth":["session","csrf"],"authRequired":true,"deprecated":true,"sunset":"2030-01-01T00:00:00Z","replacement":"POST+http://localhost/v2/orders"}]}`, rr.Body.String(); want != got {
+		t.Fatalf("want=%s, got=%s", want, got)
+	}
+
+	routes := m.Routes()
+	if want, got := sunset, routes[1].Deprecation.Sunset; !want.Equal(got) {
+		t.Fatalf("want=%v, got=%v", want, got)
+	}
+}
+
+func TestMux_Catalog_successEmpty(t *testing.T) {
+	m := &mux.Mux{}
+	c := m.Catalog()
+	if c.Name != "API" || len(c.Routes) != 0 {
+		t.Fatalf("unexpected catalog: %+v", c)
+	}
+}
+
+func TestMux_Catalog_successSameOriginIsNotAuth(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/comments", http.HandlerFunc(emptyHandler), mux.SameOrigin()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", http.HandlerFunc(emptyHandler), mux.SameOrigin(), mux.Protected("session")); err != nil {
+		t.Fatal(err)
+	}
+	c := m.Catalog()
+	if got := fmt.Sprint(c.Routes[0].Auth, c.Routes[0].AuthRequired, " ", c.Routes[1].Auth, c.Routes[1].AuthRequired); got != "[] false [session] true" {
+		t.Fatalf("want=%q, got=%q", "[] false [session] true", got)
+	}
+}
@@ -0,0 +1,58 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Checksum returns a stable hash (hex encoded SHA-256) of the canonicalized routing table, so orchestration tooling can detect drift
+//between the intended configuration and the one a running instance serves.
+//
+//The routes (in their canonical form, as shown by String method), their matchers (Eg: mux.Countries), their owners, the description of their handlers and the host aliases are hashed.
+//Two Mux with the same routes registered in different orders have the same checksum. Route options without a description (Eg: middleware) are not hashed.
+func (m *Mux) Checksum() string {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	h := sha256.New()
+	//Fields are separated by a zero byte and entries by a newline, so values cannot be confused.
+	write := func(fields ...string) {
+		for _, f := range fields {
+			h.Write([]byte(f))
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{'\n'})
+	}
+	for _, e := range m.entries {
+		r := e.route
+		write("route", r.String(), e.options.owner, describeHandler(e.handler))
+		//Matchers are part of the route identity, but not of its canonical form.
+		write("matchers", strconv.Itoa(r.protoMajor), strings.Join(r.countries, ","), strings.Join(r.exceptCountries, ","), r.userAgent.String(), r.flag, strings.Join(r.contentTypes, ","))
+	}
+	aliases := make([]string, 0, len(m.hostAliases))
+	for alias := range m.hostAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		write("alias", alias, m.hostAliases[alias])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
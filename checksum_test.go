@@ -0,0 +1,91 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Checksum_success(t *testing.T) {
+	build := func(patterns ...string) *mux.Mux {
+		m := &mux.Mux{}
+		for _, p := range patterns {
+			if err := m.Handle(http.MethodGet, p, http.HandlerFunc(emptyHandler), mux.Owner("billing")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return m
+	}
+	a := build("http://localhost/a", "http://localhost/b/{id}")
+	b := build("http://localhost/b/{id}", "http://localhost/a/")
+	if a.Checksum() != b.Checksum() {
+		t.Fatal("expected: equal checksums for equal routing tables")
+	}
+	if len(a.Checksum()) != 64 {
+		t.Fatalf("unexpected checksum: %q", a.Checksum())
+	}
+
+	sum := a.Checksum()
+	if err := a.AliasHost("www.localhost", "localhost"); err != nil {
+		t.Fatal(err)
+	}
+	if sum == a.Checksum() {
+		t.Fatal("expected: an alias changes the checksum")
+	}
+
+	c := build("http://localhost/a", "http://localhost/b/{name}")
+	if b.Checksum() == c.Checksum() {
+		t.Fatal("expected: different checksums for different routing tables")
+	}
+	if err := c.Handle(http.MethodGet, "http://localhost/c", http.HandlerFunc(emptyHandler), mux.Owner("search")); err != nil {
+		t.Fatal(err)
+	}
+	sum = c.Checksum()
+	if err := c.RemoveHandler(http.MethodGet, "http://localhost/c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Handle(http.MethodGet, "http://localhost/c", http.HandlerFunc(emptyHandler), mux.Owner("billing")); err != nil {
+		t.Fatal(err)
+	}
+	if sum == c.Checksum() {
+		t.Fatal("expected: an owner change changes the checksum")
+	}
+}
+
+func TestMux_Checksum_successMatchers(t *testing.T) {
+	options := [][]mux.RouteOption{
+		nil,
+		{mux.ProtoMajor(2)},
+		{mux.Countries("BR")},
+		{mux.ExceptCountries("BR")},
+		{mux.UserAgent("/bot/")},
+		{mux.Flag("beta")},
+		{mux.ContentTypes("application/json")},
+	}
+	sums := map[string]int{}
+	for i, opts := range options {
+		m := &mux.Mux{}
+		if err := m.Handle(http.MethodPost, "http://localhost/a", http.HandlerFunc(emptyHandler), opts...); err != nil {
+			t.Fatal(err)
+		}
+		if j, found := sums[m.Checksum()]; found {
+			t.Fatalf("expected: different checksums for options %d and %d", j, i)
+		}
+		sums[m.Checksum()] = i
+	}
+}
@@ -0,0 +1,75 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+)
+
+//Errors returned by ClientCertVars function.
+var (
+	//ErrClientCertMustExist is returned by ClientCertVars function when the request has no TLS client certificate.
+	ErrClientCertMustExist = errors.New("mux: client certificate not found")
+)
+
+//ClientCert holds the identity attributes of a TLS client certificate (mTLS).
+type ClientCert struct {
+	//CommonName is the subject common name (CN).
+	CommonName string
+	//Organizations and OrganizationalUnits are the subject organizations (O) and organizational units (OU).
+	Organizations       []string
+	OrganizationalUnits []string
+	//SerialNumber is the certificate serial number, in decimal.
+	SerialNumber string
+	//DNSNames, EmailAddresses, IPAddresses and URIs are the subject alternative names (SANs).
+	DNSNames       []string
+	EmailAddresses []string
+	IPAddresses    []string
+	URIs           []string
+}
+
+//ClientCertVars extracts the identity attributes of the client certificate of a request, so handlers can apply per-identity logic without handling the TLS state.
+//Eg: a SPIFFE ID in URIs, or the CN of a service certificate.
+//
+//The leaf certificate sent by the client is used. Verifying it is up to the server tls.Config (Eg: ClientAuth set to tls.RequireAndVerifyClientCert).
+//
+//Errors
+//
+//• mux.ErrClientCertMustExist
+func ClientCertVars(r *http.Request) (ClientCert, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ClientCert{}, ErrClientCertMustExist
+	}
+	cert := r.TLS.PeerCertificates[0]
+	cc := ClientCert{
+		CommonName:          cert.Subject.CommonName,
+		Organizations:       cert.Subject.Organization,
+		OrganizationalUnits: cert.Subject.OrganizationalUnit,
+		DNSNames:            cert.DNSNames,
+		EmailAddresses:      cert.EmailAddresses,
+	}
+	if cert.SerialNumber != nil {
+		cc.SerialNumber = cert.SerialNumber.String()
+	}
+	for _, ip := range cert.IPAddresses {
+		cc.IPAddresses = append(cc.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		cc.URIs = append(cc.URIs, uri.String())
+	}
+	return cc, nil
+}
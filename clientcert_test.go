@@ -0,0 +1,64 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestClientCertVars_success(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://example.com/billing")
+	cert := &x509.Certificate{
+		SerialNumber:   big.NewInt(42),
+		Subject:        pkix.Name{CommonName: "billing", Organization: []string{"Example"}, OrganizationalUnit: []string{"Payments"}},
+		DNSNames:       []string{"billing.internal"},
+		EmailAddresses: []string{"billing@example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{spiffe},
+	}
+	r := httptest.NewRequest(http.MethodGet, "https://localhost/", nil)
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	cc, err := mux.ClientCertVars(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cc.CommonName != "billing" || cc.Organizations[0] != "Example" || cc.OrganizationalUnits[0] != "Payments" || cc.SerialNumber != "42" {
+		t.Fatalf("unexpected subject: %+v", cc)
+	}
+	if cc.DNSNames[0] != "billing.internal" || cc.EmailAddresses[0] != "billing@example.com" || cc.IPAddresses[0] != "10.0.0.1" || cc.URIs[0] != "spiffe://example.com/billing" {
+		t.Fatalf("unexpected SANs: %+v", cc)
+	}
+}
+
+func TestClientCertVars_failClientCertMustExist(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "https://localhost/", nil)
+	if _, err := mux.ClientCertVars(r); err != mux.ErrClientCertMustExist {
+		t.Fatalf("expected: mux.ErrClientCertMustExist, got: %v", err)
+	}
+	r.TLS = &tls.ConnectionState{}
+	if _, err := mux.ClientCertVars(r); err != mux.ErrClientCertMustExist {
+		t.Fatalf("expected: mux.ErrClientCertMustExist, got: %v", err)
+	}
+}
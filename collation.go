@@ -0,0 +1,57 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+//PathCollation defines how the static path segments of routes and requests are compared.
+type PathCollation int
+
+//Path collations used in Mux.PathCollation field.
+const (
+	//PathCaseSensitive compares static path segments exactly, as RFC 3986 defines. It is the default collation.
+	PathCaseSensitive PathCollation = iota
+	//PathCaseInsensitive compares static path segments ignoring ASCII and Unicode case. Eg: /Users matches a /users route.
+	//Path variables keep the request case, and routes differing only by case conflict.
+	PathCaseInsensitive
+)
+
+//collateRoute converts the static path segments of a route to the PathCollation of the Mux, before it is inserted or searched.
+func (m *Mux) collateRoute(route *muxRoute) {
+	if m.PathCollation != PathCaseInsensitive {
+		return
+	}
+	for i, seg := range route.path {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			route.path[i] = strings.ToLower(seg)
+		}
+	}
+}
+
+//requestPathSegs splits the request path in segments, converted to the PathCollation of the Mux, so they can be compared with the routes.
+func (m *Mux) requestPathSegs(r *http.Request) []string {
+	segs := m.splitRequestPath(r)
+	if m.PathCollation == PathCaseInsensitive {
+		for i, seg := range segs {
+			segs[i] = strings.ToLower(seg)
+		}
+	}
+	return segs
+}
@@ -0,0 +1,83 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ServeHTTP_successHostCaseInsensitive(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://Example.com/users", newTestHandler("users")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AliasHost("WWW.example.com", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"example.com", "EXAMPLE.COM", "www.Example.com"} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://"+host+"/users", nil))
+		if want, got := "users", rr.Body.String(); want != got {
+			t.Fatalf("host=%q, want=%q, got=%q", host, want, got)
+		}
+	}
+	if want, got := "[GET+http://example.com/users]", fmt.Sprint(m.Routes()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_PathCollation_success(t *testing.T) {
+	tests := []struct {
+		collation mux.PathCollation
+		want      string
+	}{
+		{mux.PathCaseSensitive, "404 map[]"},
+		{mux.PathCaseInsensitive, "200 map[Id:AbC]"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{PathCollation: test.collation}
+		vars := map[string]string{}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			vars = m.PathVars(r)
+		})
+		if err := m.Handle(http.MethodGet, "http://localhost/Users/{Id}", handler); err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/uSERS/AbC", nil))
+		if got := fmt.Sprint(rr.Code, " ", vars); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestMux_PathCollation_failRouteMustNotConflict(t *testing.T) {
+	m := &mux.Mux{PathCollation: mux.PathCaseInsensitive}
+	if err := m.Handle(http.MethodGet, "http://localhost/users", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/Users", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/USERS"); err != nil {
+		t.Fatal(err)
+	}
+}
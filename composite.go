@@ -0,0 +1,197 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Errors returned by Composite methods.
+var (
+	//ErrCompositeHostMustBeValid is returned by Composite methods when the host is empty, contains URL delimiters or is an invalid wildcard host.
+	ErrCompositeHostMustBeValid = errors.New("mux: invalid composite host")
+	//ErrCompositeHostMustExist is returned by Composite methods when the host has no Mux.
+	ErrCompositeHostMustExist = errors.New("mux: composite host not found")
+	//ErrCompositeHostMustNotExist is returned by Mount method when the host already has a Mux.
+	ErrCompositeHostMustNotExist = errors.New("mux: composite host already exists")
+	//ErrMuxMustBeNotNil is returned by Composite methods when the mux parameter is nil.
+	ErrMuxMustBeNotNil = errors.New("mux: mux must be not nil")
+)
+
+//Composite delegates requests to independent Mux instances (routing tables) by request host, so each domain can be reloaded or rolled back apart from the others.
+//
+//Hosts are compared case-insensitively and may contain a port (Eg: api.example.com:8443).
+//Wildcard hosts (Eg: *.example.com or localhost:*) serve the subdomains and ports without their own Mux, the most specific wildcard first, like the wildcard hosts of Handle method.
+type Composite struct {
+	//NotFoundHandler specifies an optional `http.Handler` when the request host has no Mux.
+	//If nil, the Composite will use the default http.NotFound handler.
+	NotFoundHandler http.Handler
+	muxesLock       sync.RWMutex
+	muxes           map[string]*Mux
+}
+
+//Mount delegates the requests of a host to a Mux.
+//
+//Errors
+//
+//• mux.ErrCompositeHostMustBeValid
+//
+//• mux.ErrMuxMustBeNotNil
+//
+//• mux.ErrCompositeHostMustNotExist
+func (c *Composite) Mount(host string, m *Mux) error {
+	host, err := compositeHost(host)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrMuxMustBeNotNil
+	}
+	c.muxesLock.Lock()
+	defer c.muxesLock.Unlock()
+	if _, found := c.muxes[host]; found {
+		return ErrCompositeHostMustNotExist
+	}
+	if c.muxes == nil {
+		c.muxes = map[string]*Mux{}
+	}
+	c.muxes[host] = m
+	return nil
+}
+
+//Replace atomically swaps the Mux of a host, returning the previous one, so a reload can be rolled back by replacing it again.
+//
+//Errors
+//
+//• mux.ErrCompositeHostMustBeValid
+//
+//• mux.ErrMuxMustBeNotNil
+//
+//• mux.ErrCompositeHostMustExist
+func (c *Composite) Replace(host string, m *Mux) (*Mux, error) {
+	host, err := compositeHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrMuxMustBeNotNil
+	}
+	c.muxesLock.Lock()
+	defer c.muxesLock.Unlock()
+	previous, found := c.muxes[host]
+	if !found {
+		return nil, ErrCompositeHostMustExist
+	}
+	c.muxes[host] = m
+	return previous, nil
+}
+
+//Unmount stops delegating the requests of a host, returning its Mux.
+//
+//Errors
+//
+//• mux.ErrCompositeHostMustBeValid
+//
+//• mux.ErrCompositeHostMustExist
+func (c *Composite) Unmount(host string) (*Mux, error) {
+	host, err := compositeHost(host)
+	if err != nil {
+		return nil, err
+	}
+	c.muxesLock.Lock()
+	defer c.muxesLock.Unlock()
+	m, found := c.muxes[host]
+	if !found {
+		return nil, ErrCompositeHostMustExist
+	}
+	delete(c.muxes, host)
+	return m, nil
+}
+
+//Mux returns the Mux mounted in a host.
+//
+//Errors
+//
+//• mux.ErrCompositeHostMustBeValid
+//
+//• mux.ErrCompositeHostMustExist
+func (c *Composite) Mux(host string) (*Mux, error) {
+	host, err := compositeHost(host)
+	if err != nil {
+		return nil, err
+	}
+	c.muxesLock.RLock()
+	defer c.muxesLock.RUnlock()
+	m, found := c.muxes[host]
+	if !found {
+		return nil, ErrCompositeHostMustExist
+	}
+	return m, nil
+}
+
+//Hosts returns the sorted hosts with a mounted Mux.
+func (c *Composite) Hosts() []string {
+	c.muxesLock.RLock()
+	defer c.muxesLock.RUnlock()
+	hosts := make([]string, 0, len(c.muxes))
+	for h := range c.muxes {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+//ServeHTTP dispatches the request to the Mux of its host, or of its most specific wildcard host.
+//
+//Requests with a host without a Mux are handled by NotFoundHandler.
+func (c *Composite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := strings.ToLower(r.Host)
+	c.muxesLock.RLock()
+	m, found := c.muxes[host]
+	for _, wildcard := range wildcardHosts(host, false) {
+		if found {
+			break
+		}
+		m, found = c.muxes[wildcard]
+	}
+	c.muxesLock.RUnlock()
+	if !found {
+		c.notFound(w, r)
+		return
+	}
+	m.ServeHTTP(w, r)
+}
+
+//notFound calls a handler when a host is not found in ServeHTTP method. And if it is not set call the default http.NotFound handler.
+func (c *Composite) notFound(w http.ResponseWriter, r *http.Request) {
+	if c.NotFoundHandler == nil {
+		http.NotFound(w, r)
+		return
+	}
+	c.NotFoundHandler.ServeHTTP(w, r)
+}
+
+//compositeHost validates a Composite host, returning it lowercase.
+func compositeHost(host string) (string, error) {
+	if host == "" || strings.ContainsAny(host, "/?#@ ") || (strings.Contains(host, "*") && !validWildcardHost(host)) {
+		return "", ErrCompositeHostMustBeValid
+	}
+	return strings.ToLower(host), nil
+}
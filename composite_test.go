@@ -0,0 +1,120 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+//newHostMux creates a Mux with a root route in a host, answering with a body.
+func newHostMux(t *testing.T, host, body string) *mux.Mux {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "https://"+host+"/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestComposite_success(t *testing.T) {
+	c := &mux.Composite{}
+	if err := c.Mount("API.example.com", newHostMux(t, "api.example.com", "api v1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Mount("*.example.com", newHostMux(t, "*.example.com", "sites")); err != nil {
+		t.Fatal(err)
+	}
+	serve := func(url string) string {
+		rr := httptest.NewRecorder()
+		c.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return fmt.Sprint(rr.Code, " ", strings.TrimSpace(rr.Body.String()))
+	}
+	for url, want := range map[string]string{
+		"https://api.example.com/":  "200 api v1",
+		"https://acme.example.com/": "200 sites",
+		"https://example.org/":      "404 404 page not found",
+	} {
+		if got := serve(url); want != got {
+			t.Fatalf("%s: want=%q, got=%q", url, want, got)
+		}
+	}
+
+	//A reload is rolled back replacing the previous Mux again.
+	previous, err := c.Replace("api.example.com", newHostMux(t, "api.example.com", "api v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "200 api v2", serve("https://api.example.com/"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if _, err := c.Replace("api.example.com", previous); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "200 api v1", serve("https://api.example.com/"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	if want, got := "[*.example.com api.example.com]", fmt.Sprint(c.Hosts()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if _, err := c.Unmount("api.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "200 sites", serve("https://api.example.com/"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestComposite_fail(t *testing.T) {
+	c := &mux.Composite{NotFoundHandler: http.HandlerFunc(emptyHandler)}
+	m := &mux.Mux{}
+	if err := c.Mount("", m); err != mux.ErrCompositeHostMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrCompositeHostMustBeValid, err)
+	}
+	if err := c.Mount("*.com", m); err != mux.ErrCompositeHostMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrCompositeHostMustBeValid, err)
+	}
+	if err := c.Mount("example.com", nil); err != mux.ErrMuxMustBeNotNil {
+		t.Fatalf("want=%v, got=%v", mux.ErrMuxMustBeNotNil, err)
+	}
+	if err := c.Mount("example.com", m); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Mount("example.com", m); err != mux.ErrCompositeHostMustNotExist {
+		t.Fatalf("want=%v, got=%v", mux.ErrCompositeHostMustNotExist, err)
+	}
+	if _, err := c.Replace("example.org", m); err != mux.ErrCompositeHostMustExist {
+		t.Fatalf("want=%v, got=%v", mux.ErrCompositeHostMustExist, err)
+	}
+	if _, err := c.Mux("example.org"); err != mux.ErrCompositeHostMustExist {
+		t.Fatalf("want=%v, got=%v", mux.ErrCompositeHostMustExist, err)
+	}
+	if got, err := c.Mux("EXAMPLE.com"); err != nil || got != m {
+		t.Fatalf("unexpected mux: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	c.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "https://example.org/", nil))
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
@@ -0,0 +1,128 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//rangeConstraintRegexp validates numeric range constraints. Eg: 1-100 or -10-10 .
+var rangeConstraintRegexp = regexp.MustCompile(`^(-?[0-9]+)-(-?[0-9]+)$`)
+
+//valueConstraint is a query value test that is not a simple equality test. Eg: ?page={:1-100}, ?id={:/^[a-z]+$/} or ?format={:json|xml}
+type valueConstraint struct {
+	//isRange is true for numeric range constraints, between min and max (inclusive).
+	isRange bool
+	min     int64
+	max     int64
+	//re is used by regular expression constraints.
+	re *regexp.Regexp
+	//set is used by value set constraints.
+	set []string
+}
+
+//newValueConstraint parses a query routing value. It returns nil (without error) for simple equality tests.
+//
+//Constraints are written inside "{:" and "}":
+//
+//• Numeric ranges, with inclusive integer limits. Eg: {:1-100}
+//
+//• Regular expressions, inside slashes. Eg: {:/^[a-z]+$/}
+//
+//• Value sets, with the accepted values separated by "|". Eg: {:json|xml}
+func newValueConstraint(value string) (*valueConstraint, error) {
+	if !strings.HasPrefix(value, "{:") || !strings.HasSuffix(value, "}") {
+		return nil, nil
+	}
+	expr := value[2 : len(value)-1]
+
+	if len(expr) >= 2 && strings.HasPrefix(expr, "/") && strings.HasSuffix(expr, "/") {
+		re, err := regexp.Compile(expr[1 : len(expr)-1])
+		if err != nil {
+			return nil, ErrURLPatternInvalidQueryRoute
+		}
+		return &valueConstraint{re: re}, nil
+	}
+
+	limits := rangeConstraintRegexp.FindStringSubmatch(expr)
+	if limits == nil && strings.Contains(expr, "|") {
+		set := strings.Split(expr, "|")
+		for _, v := range set {
+			if v == "" {
+				return nil, ErrURLPatternInvalidQueryRoute
+			}
+		}
+		return &valueConstraint{set: set}, nil
+	}
+	if limits == nil {
+		return nil, ErrURLPatternInvalidQueryRoute
+	}
+	min, err := strconv.ParseInt(limits[1], 10, 64)
+	if err != nil {
+		return nil, ErrURLPatternInvalidQueryRoute
+	}
+	max, err := strconv.ParseInt(limits[2], 10, 64)
+	if err != nil || min > max {
+		return nil, ErrURLPatternInvalidQueryRoute
+	}
+	return &valueConstraint{isRange: true, min: min, max: max}, nil
+}
+
+//match tests a request query value against the constraint.
+func (c *valueConstraint) match(value string) bool {
+	if c.set != nil {
+		return containsString(c.set, value)
+	}
+	if !c.isRange {
+		return c.re.MatchString(value)
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	return err == nil && n >= c.min && n <= c.max
+}
+
+//overlaps tests if two constraints may accept the same value. Only numeric ranges and value sets can be proven disjoint.
+func (c *valueConstraint) overlaps(other *valueConstraint) bool {
+	switch {
+	case c.isRange && other.isRange:
+		return c.min <= other.max && other.min <= c.max
+	case c.set != nil:
+		for _, v := range c.set {
+			if other.match(v) {
+				return true
+			}
+		}
+		return false
+	case other.set != nil:
+		return other.overlaps(c)
+	}
+	return true
+}
+
+//example returns a value accepted by the constraint, or the fallback when it cannot be determined.
+func (c *valueConstraint) example(fallback string) string {
+	if c.set != nil {
+		return c.set[0]
+	}
+	if c.isRange {
+		return strconv.FormatInt(c.min, 10)
+	}
+	if prefix, complete := c.re.LiteralPrefix(); complete {
+		return prefix
+	}
+	return fallback
+}
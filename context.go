@@ -0,0 +1,72 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+//Errors returned by the ContextValue and ContextTimeout options.
+var (
+	//ErrContextKeyMustBeValid is returned by Handle method when the ContextValue option receives a nil or not comparable key.
+	ErrContextKeyMustBeValid = errors.New("mux: context key must be not nil and comparable")
+	//ErrContextTimeoutMustBeValid is returned by Handle method when the ContextTimeout option is not positive.
+	ErrContextTimeoutMustBeValid = errors.New("mux: context timeout must be positive")
+)
+
+//contextValue is a key/value pair injected in request contexts.
+type contextValue struct {
+	key   interface{}
+	value interface{}
+}
+
+//ContextValue attaches a key/value pair to a route, injected into the request context before the route handler (and the Observer) is called.
+//
+//So handlers and middleware can read static route configuration (Eg: feature flags or tenant configuration) using `r.Context().Value(key)`, without global maps.
+//As with `context.WithValue`, keys should be of a package specific type.
+//
+//Errors
+//
+//• mux.ErrContextKeyMustBeValid
+func ContextValue(key, value interface{}) RouteOption {
+	return func(o *routeOptions) error {
+		if key == nil || !reflect.TypeOf(key).Comparable() {
+			return ErrContextKeyMustBeValid
+		}
+		o.contextValues = append(o.contextValues, contextValue{key: key, value: value})
+		return nil
+	}
+}
+
+//ContextTimeout sets a deadline in the request context before the route handler (and the Observer) is called, so downstream calls using `r.Context()` inherit the route time budget.
+//
+//It does not interrupt the handler nor write any response: handlers and clients must honor the context. An earlier deadline of the request context is kept.
+//In groups, the last ContextTimeout option wins, so a route can override the group default.
+//
+//Errors
+//
+//• mux.ErrContextTimeoutMustBeValid
+func ContextTimeout(d time.Duration) RouteOption {
+	return func(o *routeOptions) error {
+		if d <= 0 {
+			return ErrContextTimeoutMustBeValid
+		}
+		o.contextTimeout = d
+		return nil
+	}
+}
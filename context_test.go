@@ -0,0 +1,93 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type testCtxKey string
+
+func TestMux_ContextValue_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Context().Value(testCtxKey("flag")), " ", r.Context().Value(testCtxKey("tier")))
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/beta", handler, mux.ContextValue(testCtxKey("flag"), true), mux.ContextValue(testCtxKey("tier"), "gold")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/stable", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{"/beta": "true gold", "/stable": "<nil> <nil>"} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("path=%q, want=%q, got=%q", path, want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failContextKeyMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.ContextValue(nil, "value")); err != mux.ErrContextKeyMustBeValid {
+		t.Fatal("expected: mux.ErrContextKeyMustBeValid")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.ContextValue([]string{}, "value")); err != mux.ErrContextKeyMustBeValid {
+		t.Fatal("expected: mux.ErrContextKeyMustBeValid")
+	}
+}
+
+func TestMux_ContextTimeout_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		fmt.Fprint(w, ok && time.Until(deadline) <= time.Minute)
+	})
+	api := m.Group(mux.ContextTimeout(time.Minute))
+	if err := api.Handle(http.MethodGet, "http://localhost/orders", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/stream", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{"/orders": "true", "/stream": "false"} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("path=%q, want=%q, got=%q", path, want, got)
+		}
+	}
+	if want, got := time.Minute, m.Routes()[0].ContextTimeout; want != got {
+		t.Fatalf("want=%v, got=%v", want, got)
+	}
+}
+
+func TestMux_Handle_failContextTimeoutMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.ContextTimeout(0)); err != mux.ErrContextTimeoutMustBeValid {
+		t.Fatal("expected: mux.ErrContextTimeoutMustBeValid")
+	}
+}
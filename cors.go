@@ -0,0 +1,133 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Errors returned by the CORSHeaders option.
+var (
+	//ErrCORSHeaderMustBeValid is returned by Handle method when the CORSHeaders option receives no header names or an invalid header name.
+	ErrCORSHeaderMustBeValid = errors.New("mux: invalid CORS header")
+)
+
+//anyOrigin is the CORS option origin allowing any origin.
+const anyOrigin = "*"
+
+//CORS declares the origins allowed to make cross-origin requests to the route, so the origin policy of each endpoint (Eg: stricter for admin APIs) lives in its registration call.
+//Eg: mux.CORS("https://app.example.com", "https://admin.example.com") . The "*" origin allows any origin.
+//
+//Responses to requests from allowed origins receive the Access-Control-Allow-Origin header, and every response receives the Vary: Origin header, so caches do not mix them.
+//Preflight OPTIONS requests are answered by the Mux from the options of the route of the requested method, without OPTIONS routes, unless an OPTIONS route is registered for the path.
+//Only the request headers declared by the CORSHeaders option are allowed by preflights.
+//
+//Errors
+//
+//• mux.ErrOriginMustBeValid
+func CORS(origins ...string) RouteOption {
+	return func(o *routeOptions) error {
+		if len(origins) == 0 {
+			return ErrOriginMustBeValid
+		}
+		for _, origin := range origins {
+			if origin != anyOrigin {
+				var err error
+				if origin, err = parseOrigin(origin); err != nil {
+					return err
+				}
+			}
+			o.corsOrigins = append(o.corsOrigins, origin)
+		}
+		return nil
+	}
+}
+
+//CORSHeaders declares the request headers (besides the CORS-safelisted ones) that cross-origin requests to the route may send, answered by preflights in the Access-Control-Allow-Headers header.
+//Eg: mux.CORS("https://app.example.com"), mux.CORSHeaders("Authorization", "Content-Type") . It is used with the CORS option.
+//
+//Errors
+//
+//• mux.ErrCORSHeaderMustBeValid
+func CORSHeaders(headers ...string) RouteOption {
+	return func(o *routeOptions) error {
+		if len(headers) == 0 {
+			return ErrCORSHeaderMustBeValid
+		}
+		for _, h := range headers {
+			if !validMethodToken(h) {
+				return ErrCORSHeaderMustBeValid
+			}
+			o.corsHeaders = append(o.corsHeaders, http.CanonicalHeaderKey(h))
+		}
+		return nil
+	}
+}
+
+//allowOrigin returns the Access-Control-Allow-Origin header value for a request Origin header, or an empty string when it is not allowed.
+func allowOrigin(origins []string, origin string) string {
+	u, err := url.Parse(origin)
+	switch {
+	case origin == "" || err != nil || u.Host == "":
+		return ""
+	case containsString(origins, anyOrigin):
+		return anyOrigin
+	case containsString(origins, normalizeOrigin(u.Scheme, u.Host)):
+		return origin
+	}
+	return ""
+}
+
+//allowCORS creates a handler that adds the CORS headers to the responses of the next one.
+func allowCORS(origins []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		if allowed := allowOrigin(origins, r.Header.Get("Origin")); allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+//preflight answers a CORS preflight request to a path without an OPTIONS route, when the route of the requested method allows the origin.
+//It returns false when the request was not answered.
+func (m *Mux) preflight(w http.ResponseWriter, r *http.Request) bool {
+	method := r.Header.Get("Access-Control-Request-Method")
+	if method == "" {
+		return false
+	}
+	pr := r.WithContext(r.Context())
+	pr.Method = method
+	match := m.match(pr)
+	if !match.found {
+		return false
+	}
+	allowed := allowOrigin(match.entry.options.corsOrigins, r.Header.Get("Origin"))
+	if allowed == "" {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", allowed)
+	w.Header().Add("Vary", "Origin")
+	w.Header().Set("Access-Control-Allow-Methods", method)
+	if headers := match.entry.options.corsHeaders; len(headers) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
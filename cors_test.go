@@ -0,0 +1,123 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_CORS_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPut, "http://localhost/admin/users/{id}", http.HandlerFunc(emptyHandler), mux.CORS("https://admin.example.com")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/public", http.HandlerFunc(emptyHandler), mux.CORS("*")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method      string
+		url         string
+		origin      string
+		wantStatus  int
+		wantAllowed string
+	}{
+		{http.MethodPut, "http://localhost/admin/users/1", "https://admin.example.com", http.StatusOK, "https://admin.example.com"},
+		{http.MethodPut, "http://localhost/admin/users/1", "https://ADMIN.example.com:443", http.StatusOK, "https://ADMIN.example.com:443"},
+		{http.MethodPut, "http://localhost/admin/users/1", "https://evil.example.com", http.StatusOK, ""},
+		{http.MethodGet, "http://localhost/public", "https://any.example.com", http.StatusOK, "*"},
+		{http.MethodGet, "http://localhost/public", "", http.StatusOK, ""},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.url, nil)
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if rr.Code != test.wantStatus || rr.Header().Get("Access-Control-Allow-Origin") != test.wantAllowed {
+			t.Fatalf("url=%s origin=%q, got status=%d allowed=%q", test.url, test.origin, rr.Code, rr.Header().Get("Access-Control-Allow-Origin"))
+		}
+		//Allowed or not, the response depends on the Origin header.
+		if want, got := "Origin", rr.Header().Get("Vary"); want != got {
+			t.Fatalf("url=%s origin=%q, want Vary=%q, got=%q", test.url, test.origin, want, got)
+		}
+	}
+	if origins := m.Routes()[0].CORSOrigins; len(origins) != 1 || origins[0] != "https://admin.example.com" {
+		t.Fatalf("unexpected CORSOrigins: %v", origins)
+	}
+}
+
+func TestMux_CORS_preflight(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPut, "http://localhost/admin/users/{id}", http.HandlerFunc(emptyHandler), mux.CORS("https://admin.example.com"), mux.CORSHeaders("content-type", "X-Request-Id")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodDelete, "http://localhost/admin/users/{id}", http.HandlerFunc(emptyHandler), mux.CORS("https://admin.example.com")); err != nil {
+		t.Fatal(err)
+	}
+	preflight := func(origin, method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "http://localhost/admin/users/1", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type, Cookie")
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := preflight("https://admin.example.com", http.MethodPut)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected: %d, got: %d", http.StatusNoContent, rr.Code)
+	}
+	//Only the declared headers are allowed, not the requested ones.
+	if rr.Header().Get("Access-Control-Allow-Origin") != "https://admin.example.com" || rr.Header().Get("Access-Control-Allow-Methods") != http.MethodPut || rr.Header().Get("Access-Control-Allow-Headers") != "Content-Type, X-Request-Id" {
+		t.Fatalf("unexpected headers: %v", rr.Header())
+	}
+	if rr := preflight("https://admin.example.com", http.MethodDelete); rr.Code != http.StatusNoContent || rr.Header().Get("Access-Control-Allow-Headers") != "" {
+		t.Fatalf("expected: %d without allowed headers, got: %d %v", http.StatusNoContent, rr.Code, rr.Header())
+	}
+	if rr := preflight("https://evil.example.com", http.MethodPut); rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected: %d, got: %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+	if rr := preflight("https://admin.example.com", http.MethodPatch); rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected: %d, got: %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+	if headers := m.Routes()[1].CORSHeaders; len(headers) != 2 || headers[0] != "Content-Type" {
+		t.Fatalf("unexpected CORSHeaders: %v", headers)
+	}
+}
+
+func TestMux_CORS_failOriginMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	for _, origins := range [][]string{nil, {"admin.example.com"}, {"https://admin.example.com/path"}} {
+		if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.CORS(origins...)); err != mux.ErrOriginMustBeValid {
+			t.Fatalf("origins=%v, expected: mux.ErrOriginMustBeValid, got: %v", origins, err)
+		}
+	}
+}
+
+func TestMux_CORSHeaders_failCORSHeaderMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	for _, headers := range [][]string{nil, {""}, {"Bad Header"}, {"X-Ok", "Bad:Header"}} {
+		if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.CORS("*"), mux.CORSHeaders(headers...)); err != mux.ErrCORSHeaderMustBeValid {
+			t.Fatalf("headers=%v, expected: mux.ErrCORSHeaderMustBeValid, got: %v", headers, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+//Errors returned by the DecompressBody option.
+var (
+	//ErrMaxBodySizeMustBeValid is returned by Handle method when the DecompressBody option receives a size that is not positive.
+	ErrMaxBodySizeMustBeValid = errors.New("mux: max body size must be positive")
+)
+
+//DecompressBody makes the route decompress gzip and deflate request bodies (per Content-Encoding header), so the handler receives plain bodies.
+//
+//The decompressed body is limited to maxSize bytes, protecting against decompression bombs: reading beyond it fails with a `*http.MaxBytesError`.
+//Requests with other encodings are answered with http.StatusUnsupportedMediaType, and malformed compressed bodies with http.StatusBadRequest.
+//
+//The Content-Encoding and Content-Length headers are removed from decompressed requests.
+//The decompression happens before the other per-route behaviors (Eg: Record and MirrorTo see the plain body), but after the Middleware.
+//
+//Errors
+//
+//• mux.ErrMaxBodySizeMustBeValid
+func DecompressBody(maxSize int64) RouteOption {
+	return func(o *routeOptions) error {
+		if maxSize <= 0 {
+			return ErrMaxBodySizeMustBeValid
+		}
+		o.decompressMaxSize = maxSize
+		return nil
+	}
+}
+
+//decompressBody creates a handler that decompresses the request body before calling the next one.
+func decompressBody(maxSize int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		if encoding == "" || encoding == "identity" || r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body io.ReadCloser
+		switch encoding {
+		case "gzip", "x-gzip":
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				renderError(w, r, http.StatusBadRequest, "The request body is not valid gzip.")
+				return
+			}
+			body = zr
+		case "deflate":
+			//The deflate Content-Encoding is the zlib format (RFC 1950), not the raw deflate one.
+			zr, err := zlib.NewReader(r.Body)
+			if err != nil {
+				renderError(w, r, http.StatusBadRequest, "The request body is not valid deflate.")
+				return
+			}
+			body = zr
+		default:
+			renderError(w, r, http.StatusUnsupportedMediaType, "The request Content-Encoding is not supported.")
+			return
+		}
+
+		r = r.Clone(r.Context())
+		r.Body = http.MaxBytesReader(w, body, maxSize)
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+		r.ContentLength = -1
+		next.ServeHTTP(w, r)
+	})
+}
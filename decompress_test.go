@@ -0,0 +1,91 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_DecompressBody_success(t *testing.T) {
+	m := &mux.Mux{}
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			fmt.Fprint(w, "too large")
+			return
+		}
+		fmt.Fprint(w, r.Header.Get("Content-Encoding"), string(b))
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/upload", echo, mux.DecompressBody(10)); err != nil {
+		t.Fatal(err)
+	}
+
+	gz := &bytes.Buffer{}
+	zw := gzip.NewWriter(gz)
+	zw.Write([]byte("hello"))
+	zw.Close()
+	fl := &bytes.Buffer{}
+	fw := zlib.NewWriter(fl)
+	fw.Write([]byte("world"))
+	fw.Close()
+	bomb := &bytes.Buffer{}
+	zw = gzip.NewWriter(bomb)
+	zw.Write(bytes.Repeat([]byte("0"), 1000))
+	zw.Close()
+
+	tests := []struct {
+		encoding string
+		body     io.Reader
+		want     string
+	}{
+		{"", strings.NewReader("plain"), "200 plain"},
+		{"gzip", gz, "200 hello"},
+		{"deflate", fl, "200 world"},
+		{"gzip", bomb, "200 too large"},
+		{"gzip", strings.NewReader("not gzip"), "400 Bad Request\n"},
+		{"deflate", strings.NewReader("not zlib"), "400 Bad Request\n"},
+		{"br", strings.NewReader("x"), "415 Unsupported Media Type\n"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/upload", test.body)
+		if test.encoding != "" {
+			req.Header.Set("Content-Encoding", test.encoding)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Body.String()); test.want != got {
+			t.Fatalf("%s: want=%q, got=%q", test.encoding, test.want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failMaxBodySizeMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/", http.HandlerFunc(emptyHandler), mux.DecompressBody(0)); err != mux.ErrMaxBodySizeMustBeValid {
+		t.Fatal("expected: mux.ErrMaxBodySizeMustBeValid")
+	}
+}
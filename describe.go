@@ -0,0 +1,42 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+)
+
+//Describer is implemented by handlers describing themselves in introspection output (RouteInfo.Handler and Mux.String method). Eg: "orders.Service.Create (v2)".
+type Describer interface {
+	Describe() string
+}
+
+//describeHandler returns a best-effort description of a handler: its Describe method, the function name of a `http.HandlerFunc`, or else its type name.
+func describeHandler(h http.Handler) string {
+	switch h := h.(type) {
+	case Describer:
+		return h.Describe()
+	case http.HandlerFunc:
+		if f := runtime.FuncForPC(reflect.ValueOf(h).Pointer()); f != nil {
+			return f.Name()
+		}
+	}
+	return fmt.Sprintf("%T", h)
+}
@@ -0,0 +1,107 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+//Errors returned by MountDocs method.
+var (
+	//ErrOpenAPIMustBeServed is returned by MountDocs method when ServeOpenAPI was not called before.
+	ErrOpenAPIMustBeServed = errors.New("mux: OpenAPI document must be served (ServeOpenAPI) before mounting docs")
+)
+
+//docsTemplate is the embedded API docs UI. It has no external dependencies and renders the OpenAPI document fetched from SpecURL.
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API Docs</title>
+<style>
+body{font-family:sans-serif;margin:2em;color:#222}
+.op{border:1px solid #ccc;border-radius:4px;margin:.5em 0;padding:.5em}
+.method{display:inline-block;min-width:5em;font-weight:bold;text-transform:uppercase}
+.path{font-family:monospace}
+.params,.servers{font-size:.9em;color:#555}
+</style>
+</head>
+<body>
+<h1 id="title">API Docs</h1>
+<div id="ops">Loading...</div>
+<script>
+(function() {
+	var specURL = {{.SpecURL}};
+	function el(tag, cls, text) {
+		var e = document.createElement(tag);
+		if (cls) e.className = cls;
+		if (text) e.textContent = text;
+		return e;
+	}
+	fetch(specURL).then(function(r) { return r.json(); }).then(function(spec) {
+		document.getElementById("title").textContent = spec.info.title + " " + spec.info.version;
+		var ops = document.getElementById("ops");
+		ops.textContent = "";
+		Object.keys(spec.paths).sort().forEach(function(path) {
+			Object.keys(spec.paths[path]).sort().forEach(function(method) {
+				var op = spec.paths[path][method];
+				var div = el("div", "op");
+				div.appendChild(el("span", "method", method));
+				div.appendChild(el("span", "path", path));
+				if (op.summary) div.appendChild(el("p", "", op.summary));
+				if (op.description) div.appendChild(el("p", "", op.description));
+				(op.parameters || []).forEach(function(p) {
+					var text = p.in + " " + p.name + (p.schema.enum ? " = " + p.schema.enum.join(" | ") : "");
+					div.appendChild(el("div", "params", text));
+				});
+				div.appendChild(el("div", "servers", (op.servers || []).map(function(s) { return s.url; }).join(", ")));
+				ops.appendChild(div);
+			});
+		});
+	}).catch(function(err) {
+		document.getElementById("ops").textContent = "Could not load " + specURL + ": " + err;
+	});
+})();
+</script>
+</body>
+</html>
+`))
+
+//MountDocs registers a GET route serving an embedded API docs UI, wired to the OpenAPI document registered by ServeOpenAPI.
+//
+//So services get browsable documentation from their routing table. The UI has no external dependencies.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+//
+//Errors
+//
+//• mux.ErrOpenAPIMustBeServed
+func (m *Mux) MountDocs(urlPattern string, opts ...RouteOption) error {
+	m.entriesLock.RLock()
+	specURL := m.openAPIURL
+	m.entriesLock.RUnlock()
+	if specURL == "" {
+		return ErrOpenAPIMustBeServed
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		docsTemplate.Execute(w, struct{ SpecURL string }{specURL})
+	})
+	return m.Handle(http.MethodGet, urlPattern, handler, opts...)
+}
@@ -0,0 +1,51 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_MountDocs_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.ServeOpenAPI("https://localhost/openapi.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MountDocs("https://localhost/docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://localhost/docs", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "text/html; charset=utf-8", rr.Header().Get("Content-Type"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := `var specURL = "https://localhost/openapi.json";`, rr.Body.String(); !strings.Contains(got, want) {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_MountDocs_failOpenAPIMustBeServed(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.MountDocs("https://localhost/docs"); err != mux.ErrOpenAPIMustBeServed {
+		t.Fatal("expected: mux.ErrOpenAPIMustBeServed")
+	}
+}
@@ -0,0 +1,109 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+//Matcher resolves requests to routes, without calling handlers. `*mux.Mux` implements it.
+//
+//It allows alternative routing engines to be compared against a Mux by CheckEquivalence function before they are rolled out.
+type Matcher interface {
+	//Match returns the route of a request, and false if no route matches it.
+	Match(r *http.Request) (RouteInfo, bool)
+}
+
+//Match returns the route that would handle the request in ServeHTTP, and false if the request would not be dispatched (405 and not found responses).
+//
+//The SemicolonPolicy is not applied: requests with semicolons in the query string are matched as they are.
+func (m *Mux) Match(r *http.Request) (RouteInfo, bool) {
+	match := m.match(r)
+	if !match.found {
+		return RouteInfo{}, false
+	}
+	return newRouteInfo(match.entry), true
+}
+
+//Mismatch is a request routed differently by two matchers, reported by CheckEquivalence function.
+type Mismatch struct {
+	//Request is the request of the corpus.
+	Request *http.Request
+	//A and B are the routes matched by each matcher. Found tells if a route was matched.
+	A, B           RouteInfo
+	FoundA, FoundB bool
+}
+
+//CheckEquivalence matches every request of the corpus against both matchers, returning the requests they route differently.
+//Routes are the same when they have the same method and URL pattern.
+//
+//The corpus is usually built from the registered routes by ExampleRequests method, widened by MutateRequests function, so the routing boundaries are exercised too.
+func CheckEquivalence(a, b Matcher, corpus []*http.Request) []Mismatch {
+	var mismatches []Mismatch
+	for _, r := range corpus {
+		ra, foundA := a.Match(r)
+		rb, foundB := b.Match(r)
+		if foundA == foundB && ra.Method == rb.Method && ra.URLPattern == rb.URLPattern {
+			continue
+		}
+		mismatches = append(mismatches, Mismatch{Request: r, A: ra, B: rb, FoundA: foundA, FoundB: foundB})
+	}
+	return mismatches
+}
+
+//mutationMethods are the methods tried by MutateRequests function.
+var mutationMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+
+//MutateRequests derives requests near the routing boundaries of the seed ones: the seeds themselves, the other common methods,
+//a trailing slash added or removed, an extra path segment, the last path segment removed and no query string.
+func MutateRequests(seeds []*http.Request) []*http.Request {
+	var reqs []*http.Request
+	for _, seed := range seeds {
+		reqs = append(reqs, seed)
+		for _, method := range mutationMethods {
+			if method != seed.Method {
+				reqs = append(reqs, mutateRequest(seed, method, seed.URL.Path, seed.URL.RawQuery))
+			}
+		}
+		path, query := seed.URL.Path, seed.URL.RawQuery
+		if strings.HasSuffix(path, "/") {
+			reqs = append(reqs, mutateRequest(seed, seed.Method, strings.TrimSuffix(path, "/"), query))
+		} else {
+			reqs = append(reqs, mutateRequest(seed, seed.Method, path+"/", query))
+		}
+		reqs = append(reqs, mutateRequest(seed, seed.Method, strings.TrimSuffix(path, "/")+"/extra", query))
+		if i := strings.LastIndex(strings.TrimSuffix(path, "/"), "/"); i >= 0 {
+			reqs = append(reqs, mutateRequest(seed, seed.Method, path[:i+1], query))
+		}
+		if query != "" {
+			reqs = append(reqs, mutateRequest(seed, seed.Method, path, ""))
+		}
+	}
+	return reqs
+}
+
+//mutateRequest clones a request changing its method, path and query.
+func mutateRequest(seed *http.Request, method, path, query string) *http.Request {
+	r := seed.Clone(seed.Context())
+	r.Method = method
+	u := *seed.URL
+	u.Path, u.RawPath, u.RawQuery = path, "", query
+	r.URL = &u
+	r.RequestURI = ""
+	return r
+}
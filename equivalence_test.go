@@ -0,0 +1,73 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+//pathMatcher is a naive engine, matching only static paths.
+type pathMatcher map[string]mux.RouteInfo
+
+func (pm pathMatcher) Match(r *http.Request) (mux.RouteInfo, bool) {
+	ri, ok := pm[r.Method+" "+r.URL.Path]
+	return ri, ok
+}
+
+func TestCheckEquivalence_success(t *testing.T) {
+	m := &mux.Mux{}
+	for _, pattern := range []string{"http://localhost/", "http://localhost/users", "http://localhost/users/{id}"} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pm := pathMatcher{}
+	for _, ri := range m.Routes() {
+		pm[ri.Method+" "+ri.URLPattern[len("http://localhost"):]] = ri
+	}
+
+	corpus := mux.MutateRequests(m.ExampleRequests())
+	if want, got := 0, len(mux.CheckEquivalence(m, m, corpus)); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+
+	//The naive engine fails on path variables and trailing slashes.
+	got := ""
+	for _, mm := range mux.CheckEquivalence(m, pm, corpus) {
+		got += fmt.Sprint(mm.Request.Method, " ", mm.Request.URL.Path, " ", mm.FoundA, " ", mm.FoundB, ";")
+	}
+	if want := "GET  true false;GET /users/ true false;GET /users/extra true false;GET /users/id true false;GET /users/id/ true false;GET /users/ true false;"; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Match_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/users/{id}", http.HandlerFunc(emptyHandler), mux.Owner("users")); err != nil {
+		t.Fatal(err)
+	}
+	ri, found := m.Match(httptest.NewRequest(http.MethodGet, "http://localhost/users/1", nil))
+	if want, got := "true GET+http://localhost/users/{id} users", fmt.Sprint(found, " ", ri, " ", ri.Owner); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if _, found := m.Match(httptest.NewRequest(http.MethodPost, "http://localhost/users/1", nil)); found {
+		t.Fatal("expected: not found")
+	}
+}
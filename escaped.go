@@ -0,0 +1,66 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//EncodedSlashPolicy defines how encoded slashes (%2F) in request paths are handled before matching.
+//
+//Request paths are always matched escaped (See `http.Request.URL.EscapedPath`), so a path variable may contain an encoded slash. PathVars and PathValues methods decode the other values, unless Mux.EscapedPathVars is set.
+type EncodedSlashPolicy int
+
+//Encoded slash policies used in Mux.EncodedSlashPolicy field.
+const (
+	//EncodedSlashKeep treats encoded slashes as part of the path segment, so path variables may contain them (Eg: file names or git refs). It is the default policy.
+	EncodedSlashKeep EncodedSlashPolicy = iota
+	//EncodedSlashSeparate treats encoded slashes as path segments separators, like "/".
+	EncodedSlashSeparate
+)
+
+//splitRequestPath splits the escaped request path in segments, following the EncodedSlashPolicy of the Mux.
+func (m *Mux) splitRequestPath(r *http.Request) []string {
+	return splitEscapedPath(r.URL.EscapedPath(), m.EncodedSlashPolicy)
+}
+
+//splitEscapedPath splits an escaped path in segments, following an EncodedSlashPolicy.
+func splitEscapedPath(path string, policy EncodedSlashPolicy) []string {
+	return splitPathSegs(separateEncodedSlashes(path, policy))
+}
+
+//separateEncodedSlashes replaces the encoded slashes of an escaped path by "/", when the EncodedSlashPolicy treats them as separators.
+func separateEncodedSlashes(path string, policy EncodedSlashPolicy) string {
+	if policy == EncodedSlashSeparate {
+		return strings.NewReplacer("%2F", "/", "%2f", "/").Replace(path)
+	}
+	return path
+}
+
+//pathVarSegs splits the escaped request path in the segments used by path variables.
+//
+//Unless escaped is set, they are decoded as http.Request.URL.Path, except when the escaped path has a meaning of its own (Eg: an encoded slash), as http.Request.URL.RawPath.
+func (m *Mux) pathVarSegs(path string, escaped bool) []string {
+	path = separateEncodedSlashes(path, m.EncodedSlashPolicy)
+	if !escaped {
+		if unescaped, err := url.PathUnescape(path); err == nil && (&url.URL{Path: unescaped}).EscapedPath() == path {
+			path = unescaped
+		}
+	}
+	return splitPathSegs(path)
+}
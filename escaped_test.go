@@ -0,0 +1,100 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_EncodedSlashPolicy_success(t *testing.T) {
+	tests := []struct {
+		policy mux.EncodedSlashPolicy
+		url    string
+		want   string
+	}{
+		{mux.EncodedSlashKeep, "http://localhost/files/docs%2Freadme.md", "file map[name:docs%2Freadme.md]"},
+		{mux.EncodedSlashKeep, "http://localhost/files/docs/readme.md", "dir map[dir:docs name:readme.md]"},
+		{mux.EncodedSlashSeparate, "http://localhost/files/docs%2Freadme.md", "dir map[dir:docs name:readme.md]"},
+		{mux.EncodedSlashSeparate, "http://localhost/files/docs%2freadme.md", "dir map[dir:docs name:readme.md]"},
+		{mux.EncodedSlashSeparate, "http://localhost/files/readme.md", "file map[name:readme.md]"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{EncodedSlashPolicy: test.policy}
+		handler := func(body string) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, body, " ", m.PathVars(r))
+			})
+		}
+		if err := m.Handle(http.MethodGet, "http://localhost/files/{name}", handler("file")); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Handle(http.MethodGet, "http://localhost/files/{dir}/{name}", handler("dir")); err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("policy=%v, url=%q, want=%q, got=%q", test.policy, test.url, test.want, got)
+		}
+	}
+}
+
+func TestMux_EncodedSlashPolicy_successTypedVars(t *testing.T) {
+	m := &mux.Mux{EncodedSlashPolicy: mux.EncodedSlashSeparate}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders/{id:int}/{item}", newTestHandler("item")); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/orders/12%2Fbook", nil))
+	if want, got := "item", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if got := fmt.Sprint(m.PathValues(httptest.NewRequest(http.MethodGet, "http://localhost/orders/12%2Fbook", nil))); got != "[12 book]" {
+		t.Fatalf("want=%q, got=%q", "[12 book]", got)
+	}
+}
+
+func TestMux_PathVars_successDecoded(t *testing.T) {
+	tests := []struct {
+		escaped bool
+		url     string
+		want    string
+	}{
+		{false, "http://localhost/files/a%20b", "map[name:a b] [a b]"},
+		{false, "http://localhost/files/%C3%A9", "map[name:é] [é]"},
+		{false, "http://localhost/files/a%2520b", "map[name:a%20b] [a%20b]"},
+		{false, "http://localhost/files/docs%2Freadme.md", "map[name:docs%2Freadme.md] [docs%2Freadme.md]"},
+		{true, "http://localhost/files/a%20b", "map[name:a%20b] [a%20b]"},
+		{true, "http://localhost/files/%C3%A9", "map[name:%C3%A9] [%C3%A9]"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{EscapedPathVars: test.escaped}
+		if err := m.Handle(http.MethodGet, "http://localhost/files/{name}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, m.PathVars(r), " ", m.PathValues(r))
+		})); err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("escaped=%v, url=%q, want=%q, got=%q", test.escaped, test.url, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//ETag makes the route compute a strong ETag of its http.StatusOK responses to GET and HEAD requests, answering matching If-None-Match requests with http.StatusNotModified,
+//so frequently polled endpoints (Eg: JSON status documents) save bandwidth.
+//
+//The response is buffered to be hashed, so the option is ignored by Streaming routes. An ETag set by the handler is kept and compared instead.
+func ETag() RouteOption {
+	return func(o *routeOptions) error {
+		o.etag = true
+		return nil
+	}
+}
+
+//computeETag creates a handler that buffers the responses of the next one, adding an ETag and answering conditional requests.
+func computeETag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		bw := &bufferWriter{header: http.Header{}}
+		next.ServeHTTP(bw, r)
+		for name, values := range bw.header {
+			w.Header()[name] = values
+		}
+		status := bw.statusCode()
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			w.Write(bw.body.Bytes())
+			return
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(bw.body.Bytes())
+			etag = `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+		}
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if w.Header().Get("Content-Length") == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(bw.body.Len()))
+		}
+		w.WriteHeader(status)
+		w.Write(bw.body.Bytes())
+	})
+}
+
+//etagMatches tests if an If-None-Match header matches an ETag, using the weak comparison (RFC 7232).
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ETag_success(t *testing.T) {
+	m := &mux.Mux{}
+	body := `{"status":"ok"}`
+	if err := m.Handle(http.MethodGet, "http://localhost/status", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}), mux.ETag()); err != nil {
+		t.Fatal(err)
+	}
+	serve := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve("")
+	etag := rr.Header().Get("ETag")
+	if want, got := fmt.Sprint("200 true application/json 15 ", body), fmt.Sprint(rr.Code, " ", len(etag) > 2, " ", rr.Header().Get("Content-Type"), " ", rr.Header().Get("Content-Length"), " ", rr.Body.String()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	for _, ifNoneMatch := range []string{etag, `"other", W/` + etag, "*"} {
+		rr = serve(ifNoneMatch)
+		if want, got := "304 "+etag+" ", fmt.Sprint(rr.Code, " ", rr.Header().Get("ETag"), " ", rr.Body.String()); want != got {
+			t.Fatalf("%s: want=%q, got=%q", ifNoneMatch, want, got)
+		}
+	}
+
+	//A changed response has another ETag.
+	body = `{"status":"degraded"}`
+	rr = serve(etag)
+	if want, got := http.StatusOK, rr.Code; want != got || rr.Header().Get("ETag") == etag {
+		t.Fatalf("want=%d, got=%d %s", want, got, rr.Header().Get("ETag"))
+	}
+}
+
+func TestMux_ETag_successPassThrough(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("items"))
+	}), mux.ETag()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/missing", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}), mux.ETag()); err != nil {
+		t.Fatal(err)
+	}
+
+	//The ETag set by the handler is compared.
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/items", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := http.StatusNotModified, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+
+	//Other statuses are not tagged.
+	rr = httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/missing", nil))
+	if want, got := "404 ", fmt.Sprint(rr.Code, " ", rr.Header().Get("ETag")); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
@@ -0,0 +1,122 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+//Errors returned by the Faults option.
+var (
+	//ErrFaultInjectorMustBeNotNil is returned by Handle method when the Faults option receives a nil FaultInjector.
+	ErrFaultInjectorMustBeNotNil = errors.New("mux: FaultInjector must be not nil")
+)
+
+//FaultInjector injects faults (latency, errors and connection resets) in the requests of the routes it is attached to by the Faults option.
+//
+//It is intended for resilience testing, so it starts disabled and must be toggled at runtime by Enable and Disable methods.
+//The same FaultInjector can be attached to a group of routes to toggle them together.
+//
+//The fields must not be changed after the FaultInjector is attached to a route.
+type FaultInjector struct {
+	//Latency is added before the handler is called (or the fault is injected).
+	Latency time.Duration
+	//ErrorRate is the fraction (between 0 and 1) of requests answered with ErrorStatus instead of calling the handler.
+	ErrorRate float64
+	//ErrorStatus is the HTTP status code of injected errors. If zero, http.StatusServiceUnavailable is used.
+	ErrorStatus int
+	//ResetRate is the fraction (between 0 and 1) of requests that have their connection aborted instead of calling the handler.
+	ResetRate float64
+	enabled   int32
+}
+
+//Faults attaches a FaultInjector to a route.
+//
+//Errors
+//
+//• mux.ErrFaultInjectorMustBeNotNil
+func Faults(fi *FaultInjector) RouteOption {
+	return func(o *routeOptions) error {
+		if fi == nil {
+			return ErrFaultInjectorMustBeNotNil
+		}
+		o.faults = fi
+		return nil
+	}
+}
+
+//Enable starts injecting faults.
+func (fi *FaultInjector) Enable() {
+	atomic.StoreInt32(&fi.enabled, 1)
+}
+
+//Disable stops injecting faults.
+func (fi *FaultInjector) Disable() {
+	atomic.StoreInt32(&fi.enabled, 0)
+}
+
+//Enabled tests if faults are being injected.
+func (fi *FaultInjector) Enabled() bool {
+	return atomic.LoadInt32(&fi.enabled) == 1
+}
+
+//wrap creates a handler that injects the faults before calling the next one.
+func (fi *FaultInjector) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fi.inject(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+//inject applies the faults to a request, returning true when the request was answered and the handler must not be called.
+func (fi *FaultInjector) inject(w http.ResponseWriter, r *http.Request) bool {
+	if !fi.Enabled() {
+		return false
+	}
+
+	//Add latency, but give up if the client is gone.
+	if fi.Latency > 0 {
+		t := time.NewTimer(fi.Latency)
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			t.Stop()
+			return true
+		}
+	}
+
+	//Abort the connection. The http.Server recovers from http.ErrAbortHandler silently.
+	if fi.ResetRate > 0 && rand.Float64() < fi.ResetRate {
+		panic(http.ErrAbortHandler)
+	}
+
+	//Or answer with an error.
+	if fi.ErrorRate > 0 && rand.Float64() < fi.ErrorRate {
+		status := fi.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		renderError(w, r, status, "The fault injector answered with an error.")
+		return true
+	}
+	return false
+}
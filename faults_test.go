@@ -0,0 +1,79 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Faults_success(t *testing.T) {
+	errors := &mux.FaultInjector{ErrorRate: 1, ErrorStatus: http.StatusBadGateway, Latency: time.Millisecond}
+	resets := &mux.FaultInjector{ResetRate: 1}
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/payments", newTestHandler("payments"), mux.Faults(errors)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", newTestHandler("orders"), mux.Faults(resets)); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(url string) (rr *httptest.ResponseRecorder, aborted bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				if r != http.ErrAbortHandler {
+					panic(r)
+				}
+				aborted = true
+			}
+		}()
+		rr = httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr, false
+	}
+
+	//Disabled by default.
+	if rr, _ := serve("http://localhost/payments"); rr.Body.String() != "payments" {
+		t.Fatalf("want=%q, got=%q", "payments", rr.Body.String())
+	}
+	if _, aborted := serve("http://localhost/orders"); aborted {
+		t.Fatal("expected: not aborted")
+	}
+
+	errors.Enable()
+	resets.Enable()
+	if rr, _ := serve("http://localhost/payments"); rr.Code != http.StatusBadGateway {
+		t.Fatalf("want=%d, got=%d", http.StatusBadGateway, rr.Code)
+	}
+	if _, aborted := serve("http://localhost/orders"); !aborted {
+		t.Fatal("expected: aborted")
+	}
+
+	errors.Disable()
+	if rr, _ := serve("http://localhost/payments"); rr.Body.String() != "payments" {
+		t.Fatalf("want=%q, got=%q", "payments", rr.Body.String())
+	}
+}
+
+func TestMux_Handle_failFaultInjectorMustBeNotNil(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Faults(nil)); err != mux.ErrFaultInjectorMustBeNotNil {
+		t.Fatal("expected: mux.ErrFaultInjectorMustBeNotNil")
+	}
+}
@@ -0,0 +1,61 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+)
+
+//Errors returned by the Flag option.
+var (
+	//ErrFlagMustBeValid is returned by Handle and RemoveHandler methods when the Flag option receives an empty key.
+	ErrFlagMustBeValid = errors.New("mux: feature flag key must be not empty")
+)
+
+//FlagProvider tells if feature flags are enabled, set in Mux.FlagProvider field. Eg: backed by a feature flag service.
+//
+//Enabled is called concurrently, on each request to a flagged route, so it should be cheap.
+type FlagProvider interface {
+	//Enabled tells if the flag is enabled for the request, so flags can target users or a percentage of requests.
+	Enabled(key string, r *http.Request) bool
+}
+
+//Flag is a matcher option that makes a route appear only while a feature flag is enabled by Mux.FlagProvider.
+//
+//When the flag is disabled, requests fall through to the next candidate, so an alternate handler can be registered in the same route without the Flag option,
+//or else they are answered by NotFoundHandler. See `mux.ProtoMajor`.
+//
+//Errors
+//
+//• mux.ErrFlagMustBeValid
+func Flag(key string) RouteOption {
+	return func(o *routeOptions) error {
+		if key == "" {
+			return ErrFlagMustBeValid
+		}
+		o.flag = key
+		return nil
+	}
+}
+
+//flagSet returns a flag key as a set, so it can be compared by compareOptionalStrings.
+func flagSet(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return []string{key}
+}
@@ -0,0 +1,84 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type testFlagProvider struct {
+	sync.Mutex
+	enabled map[string]bool
+}
+
+func (p *testFlagProvider) Enabled(key string, r *http.Request) bool {
+	p.Lock()
+	defer p.Unlock()
+	return p.enabled[key]
+}
+
+func (p *testFlagProvider) set(key string, enabled bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.enabled[key] = enabled
+}
+
+func TestMux_Flag_success(t *testing.T) {
+	flags := &testFlagProvider{enabled: map[string]bool{}}
+	m := &mux.Mux{FlagProvider: flags}
+	if err := m.Handle(http.MethodGet, "http://localhost/checkout", newTestHandler("new checkout"), mux.Flag("new-checkout")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/checkout", newTestHandler("old checkout")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/beta", newTestHandler("beta"), mux.Flag("beta")); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(path string) string {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		return fmt.Sprint(rr.Code, " ", rr.Body.String())
+	}
+	if want, got := "200 old checkout", serve("/checkout"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "404 404 page not found\n", serve("/beta"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	flags.set("new-checkout", true)
+	flags.set("beta", true)
+	if want, got := "200 new checkout", serve("/checkout"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "200 beta", serve("/beta"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Handle_failFlagMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Flag("")); err != mux.ErrFlagMustBeValid {
+		t.Fatal("expected: mux.ErrFlagMustBeValid")
+	}
+}
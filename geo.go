@@ -0,0 +1,126 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"strings"
+)
+
+//Errors returned by country matcher options.
+var (
+	//ErrCountryMustBeValid is returned by Handle and RemoveHandler methods when the Countries or ExceptCountries options receive no codes or a code that is not two ASCII letters.
+	ErrCountryMustBeValid = errors.New("mux: country must be an ISO 3166-1 alpha-2 code")
+)
+
+//GeoResolver resolves the country of an IP address, set in Mux.GeoResolver field. Eg: backed by a GeoIP database.
+type GeoResolver interface {
+	//Country returns the ISO 3166-1 alpha-2 code (Eg: "BR") of the IP address country.
+	Country(ip net.IP) (string, error)
+}
+
+//Countries is a matcher option that constrains a route to requests from clients in the countries (ISO 3166-1 alpha-2 codes, Eg: "BR").
+//
+//The client IP is extracted from `*http.Request.RemoteAddr` and resolved by Mux.GeoResolver. Requests whose country cannot be resolved do not match.
+//As any matcher, it is part of the route identity and unmatched requests fall through to the next candidate (or NotFoundHandler). See `mux.ProtoMajor`.
+//
+//Errors
+//
+//• mux.ErrCountryMustBeValid
+func Countries(codes ...string) RouteOption {
+	return func(o *routeOptions) error {
+		countries, err := newCountries(codes)
+		if err != nil {
+			return err
+		}
+		o.countries = countries
+		return nil
+	}
+}
+
+//ExceptCountries is a matcher option that constrains a route to requests from clients not in the countries. Requests whose country cannot be resolved match.
+//
+//Check the `mux.Countries` option for details.
+//
+//Errors
+//
+//• mux.ErrCountryMustBeValid
+func ExceptCountries(codes ...string) RouteOption {
+	return func(o *routeOptions) error {
+		countries, err := newCountries(codes)
+		if err != nil {
+			return err
+		}
+		o.exceptCountries = countries
+		return nil
+	}
+}
+
+//newCountries validates, normalizes to upper case and sorts country codes.
+func newCountries(codes []string) ([]string, error) {
+	if len(codes) == 0 {
+		return nil, ErrCountryMustBeValid
+	}
+	countries := make([]string, len(codes))
+	for i, code := range codes {
+		code = strings.ToUpper(code)
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return nil, ErrCountryMustBeValid
+		}
+		countries[i] = code
+	}
+	sort.Strings(countries)
+	return countries, nil
+}
+
+//acceptsCountry tests the route country matchers against the request client country.
+func (r *muxRoute) acceptsCountry(rm *requestMatch) bool {
+	if len(r.countries) == 0 && len(r.exceptCountries) == 0 {
+		return true
+	}
+	country := rm.resolveCountry()
+	if len(r.countries) > 0 && !containsString(r.countries, country) {
+		return false
+	}
+	return !containsString(r.exceptCountries, country)
+}
+
+//resolveCountry resolves the request client country once. It returns "" if it cannot be resolved.
+func (rm *requestMatch) resolveCountry() string {
+	if rm.resolved {
+		return rm.country
+	}
+	rm.resolved = true
+	if rm.geo == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(rm.req.RemoteAddr)
+	if err != nil {
+		host = rm.req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	country, err := rm.geo.Country(ip)
+	if err != nil {
+		return ""
+	}
+	rm.country = strings.ToUpper(country)
+	return rm.country
+}
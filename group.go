@@ -0,0 +1,140 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Query adds query routing tests to a route, as if they were written in the URL pattern. Eg: mux.Query("api-key&version=2").
+//
+//It is mostly useful in groups, so a query pattern can be declared once and inherited by all routes registered through the group.
+//Conflict detection takes the added tests into account.
+//
+//Errors
+//
+//• mux.ErrURLPatternInvalidQueryRoute
+func Query(query string) RouteOption {
+	return func(o *routeOptions) error {
+		values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+		if err != nil {
+			return ErrURLPatternInvalidQueryRoute
+		}
+		if o.query == nil {
+			o.query = url.Values{}
+		}
+		for name, paramValues := range values {
+			o.query[name] = append(o.query[name], paramValues...)
+		}
+		return nil
+	}
+}
+
+//Group registers routes in a Mux sharing a set of RouteOption functions.
+//
+//Routes registered through a group inherit the group options, applied before the options of each route.
+//Groups created by GroupPrefix method also prefix the URL patterns of their routes with a scheme, host and path.
+type Group struct {
+	mux     *Mux
+	options []RouteOption
+	gopts   GroupOptions
+	//prefix is the URL prefix of the group routes. Eg: https://api.example.com/v1
+	prefix string
+	//err is the error of an invalid nested prefix, returned when routes are registered.
+	err error
+}
+
+//Group creates a group of routes sharing the opts functions.
+func (m *Mux) Group(opts ...RouteOption) *Group {
+	return &Group{mux: m, options: opts}
+}
+
+//GroupWith creates a group of routes sharing the opts functions, with the behavior set by gopts.
+func (m *Mux) GroupWith(gopts GroupOptions, opts ...RouteOption) *Group {
+	return &Group{mux: m, options: opts, gopts: gopts}
+}
+
+//GroupPrefix creates a group of routes sharing the opts functions and an URL prefix (Eg: https://api.example.com/v1), so routes are registered with relative URL patterns.
+//
+//The URL patterns of the group routes are appended to the prefix, so they must be empty or start with a path (Eg: /users/{id}) or a query (Eg: ?page={page}).
+//Other URL patterns are rejected with mux.ErrURLPatternMustBeValid when the routes are registered.
+func (m *Mux) GroupPrefix(urlPrefix string, opts ...RouteOption) *Group {
+	return &Group{mux: m, options: opts, prefix: urlPrefix}
+}
+
+//Group creates a nested group inheriting the options (and the GroupOptions and prefix) of this group, followed by opts.
+func (g *Group) Group(opts ...RouteOption) *Group {
+	return &Group{mux: g.mux, options: g.with(opts), gopts: g.gopts, prefix: g.prefix, err: g.err}
+}
+
+//GroupPrefix creates a nested group like Group method, appending a path prefix (Eg: /admin) to the prefix of this group.
+//If this group has no prefix, the prefix must be an URL prefix, as in `mux.Mux.GroupPrefix` method.
+func (g *Group) GroupPrefix(prefix string, opts ...RouteOption) *Group {
+	ng := g.Group(opts...)
+	if g.prefix == "" {
+		ng.prefix = prefix
+		return ng
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		ng.err = ErrURLPatternMustBeValid
+	}
+	ng.prefix = strings.TrimSuffix(g.prefix, "/") + prefix
+	return ng
+}
+
+//Handle creates a routing entry with the group options. See `mux.Mux.Handle`.
+func (g *Group) Handle(httpMethod string, urlPattern string, handler http.Handler, opts ...RouteOption) error {
+	urlPattern, err := g.pattern(urlPattern)
+	if err != nil {
+		return err
+	}
+	return g.mux.Handle(httpMethod, urlPattern, handler, g.with(opts)...)
+}
+
+//RemoveHandler removes a handler registered through the group. See `mux.Mux.RemoveHandler`.
+func (g *Group) RemoveHandler(httpMethod, urlPattern string, opts ...RouteOption) error {
+	urlPattern, err := g.pattern(urlPattern)
+	if err != nil {
+		return err
+	}
+	return g.mux.RemoveHandler(httpMethod, urlPattern, g.with(opts)...)
+}
+
+//pattern appends an URL pattern to the group prefix.
+func (g *Group) pattern(urlPattern string) (string, error) {
+	if g.err != nil {
+		return "", g.err
+	}
+	if g.prefix == "" {
+		return urlPattern, nil
+	}
+	if strings.Contains(g.prefix, "?") || !(urlPattern == "" || strings.HasPrefix(urlPattern, "/") || strings.HasPrefix(urlPattern, "?")) {
+		return "", ErrURLPatternMustBeValid
+	}
+	return strings.TrimSuffix(g.prefix, "/") + urlPattern, nil
+}
+
+//with appends opts to the group options.
+func (g *Group) with(opts []RouteOption) []RouteOption {
+	all := make([]RouteOption, 0, len(g.options)+len(opts)+1)
+	all = append(all, g.options...)
+	if g.gopts.MiddlewareFirst {
+		all = append(all, markFirstMiddleware)
+	}
+	return append(all, opts...)
+}
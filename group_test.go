@@ -0,0 +1,118 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestGroup_Handle_successInheritedQuery(t *testing.T) {
+	m := &mux.Mux{}
+	api := m.Group(mux.Query("api-key"), mux.Owner("api"))
+	if err := api.Handle(http.MethodGet, "http://localhost/orders", newTestHandler("orders")); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.Group(mux.Query("version=2")).Handle(http.MethodGet, "http://localhost/orders?format=csv", newTestHandler("orders v2 csv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", newTestHandler("public orders")); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "[GET+http://localhost/orders?api-key&format=csv&version=2 GET+http://localhost/orders?api-key GET+http://localhost/orders]", fmt.Sprint(m.Routes()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	tests := map[string]string{
+		"?api-key=secret":                      "orders",
+		"?api-key=secret&version=2&format=csv": "orders v2 csv",
+		"":                                     "public orders",
+	}
+	for query, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/orders"+query, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("query=%q, want=%q, got=%q", query, want, got)
+		}
+	}
+
+	//Conflicts take the inherited query into account.
+	if err := m.Handle(http.MethodGet, "http://localhost/orders?api-key", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := api.Handle(http.MethodGet, "http://localhost/orders?api-key=x", http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternInvalidQueryRoute {
+		t.Fatal("expected: mux.ErrURLPatternInvalidQueryRoute")
+	}
+
+	if err := api.RemoveHandler(http.MethodGet, "http://localhost/orders"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestGroup_GroupPrefix_success(t *testing.T) {
+	m := &mux.Mux{}
+	v1 := m.GroupPrefix("https://api.example.com/v1/", mux.Use(newTraceMiddleware("v1")))
+	admin := v1.GroupPrefix("/admin", mux.Use(newTraceMiddleware("admin")))
+	if err := v1.Handle(http.MethodGet, "", newTestHandler("index")); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1.Handle(http.MethodGet, "/users/{id}", newTestHandler("user")); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1.Group().Handle(http.MethodGet, "/orders?page={page}", newTestHandler("orders")); err != nil {
+		t.Fatal(err)
+	}
+	if err := admin.Handle(http.MethodDelete, "/users/{id}", newTestHandler("deleted")); err != nil {
+		t.Fatal(err)
+	}
+	want := "[GET+https://api.example.com/v1 DELETE+https://api.example.com/v1/admin/users/{id} GET+https://api.example.com/v1/orders?page={page} GET+https://api.example.com/v1/users/{id}]"
+	if got := fmt.Sprint(m.Routes()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "https://api.example.com/v1/admin/users/42", nil))
+	if want, got := "v1>admin>deleted", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if err := admin.RemoveHandler(http.MethodDelete, "/users/{id}"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGroup_GroupPrefix_failURLPatternMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	v1 := m.GroupPrefix("https://api.example.com/v1")
+	for _, g := range []*mux.Group{v1, v1.GroupPrefix("admin"), m.GroupPrefix("https://api.example.com/v1?key")} {
+		for _, pattern := range []string{"users", "https://api.example.com/users", "/users"} {
+			if g == v1 && pattern == "/users" {
+				continue
+			}
+			if err := g.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternMustBeValid {
+				t.Fatalf("pattern=%q, expected: mux.ErrURLPatternMustBeValid, got=%v", pattern, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Errors returned by the Header option.
+var (
+	//ErrHeaderTemplateMustBeValid is returned by Handle method when the Header option receives an invalid header name,
+	//or a value template with unbalanced braces or referencing a variable that the route does not have.
+	ErrHeaderTemplateMustBeValid = errors.New("mux: invalid header template")
+)
+
+//headerTemplate is a default response header of a route, set by the Header option.
+type headerTemplate struct {
+	name  string
+	value string
+	//vars are the variable names referenced by the value.
+	vars []string
+}
+
+//Header sets a default response header of a route. The value is a template that may reference the route variables (path, host and query capture variables) between braces,
+//replaced by their (unescaped) request values at dispatch. Eg: mux.Header("Content-Disposition", "attachment; filename={name}.csv") for https://host/reports/{name} .
+//
+//The header is set before the handler is called, so the handler can still override it.
+//
+//Variable values could inject header parameters or split the header (Eg: x.csv%3B%20filename=evil.exe), so requests whose referenced values have
+//quotes, semicolons, commas, backslashes or control characters (Eg: CR and LF) are answered with http.StatusBadRequest, without calling the handler.
+//
+//Errors
+//
+//• mux.ErrHeaderTemplateMustBeValid
+func Header(name, valueTemplate string) RouteOption {
+	return func(o *routeOptions) error {
+		vars, ok := parseHeaderTemplate(valueTemplate)
+		if !ok || !validMethodToken(name) {
+			return ErrHeaderTemplateMustBeValid
+		}
+		o.headers = append(o.headers, headerTemplate{name: http.CanonicalHeaderKey(name), value: valueTemplate, vars: vars})
+		return nil
+	}
+}
+
+//parseHeaderTemplate extracts the variable names referenced by a header value template. It returns false for unbalanced or empty braces.
+func parseHeaderTemplate(tpl string) ([]string, bool) {
+	vars := []string{}
+	for rest := tpl; ; {
+		start, end := strings.Index(rest, "{"), strings.Index(rest, "}")
+		if start == -1 && end == -1 {
+			return vars, true
+		}
+		if start == -1 || end < start {
+			return nil, false
+		}
+		name := rest[start+1 : end]
+		if name == "" || strings.Contains(name, "{") {
+			return nil, false
+		}
+		vars = append(vars, name)
+		rest = rest[end+1:]
+	}
+}
+
+//checkHeaderTemplates tests if the variables referenced by the header templates exist in the route.
+func checkHeaderTemplates(route *muxRoute, headers []headerTemplate) error {
+	for _, h := range headers {
+		for _, name := range h.vars {
+			_, isPathVar := route.vars[name]
+			if !isPathVar && name != route.hostVar && !route.query.captures(name) {
+				return ErrHeaderTemplateMustBeValid
+			}
+		}
+	}
+	return nil
+}
+
+//captures tests if a query capture variable exists in the query routing.
+func (qr queryRoute) captures(name string) bool {
+	for _, q := range qr {
+		if q.capture == name {
+			return true
+		}
+	}
+	return false
+}
+
+//expand replaces the variables of the header value.
+func (h headerTemplate) expand(vars map[string]string) string {
+	if len(h.vars) == 0 {
+		return h.value
+	}
+	pairs := make([]string, 0, 2*len(h.vars))
+	for _, name := range h.vars {
+		pairs = append(pairs, "{"+name+"}", vars[name])
+	}
+	return strings.NewReplacer(pairs...).Replace(h.value)
+}
+
+//setHeaders creates a handler that sets the default response headers before calling the next one.
+func setHeaders(headers []headerTemplate, next http.Handler) http.Handler {
+	needsVars := false
+	for _, h := range headers {
+		needsVars = needsVars || len(h.vars) > 0
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := map[string]string{}
+		if s, ok := stateOf(r); ok && needsVars {
+			for name, value := range s.mux.pathVars(r, true) {
+				if unescaped, err := url.PathUnescape(value); err == nil {
+					value = unescaped
+				}
+				vars[name] = value
+			}
+			for name, value := range s.mux.QueryVars(r) {
+				vars[name] = value
+			}
+		}
+		for _, h := range headers {
+			for _, name := range h.vars {
+				if !safeHeaderValue(vars[name]) {
+					renderError(w, r, http.StatusBadRequest, "The request variables cannot be used in response headers.")
+					return
+				}
+			}
+		}
+		for _, h := range headers {
+			w.Header().Set(h.name, h.expand(vars))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+//safeHeaderValue tests if a variable value can be put in a header value template without adding parameters, list elements or lines to the header.
+func safeHeaderValue(value string) bool {
+	for _, c := range value {
+		if c < ' ' || c == 0x7f || strings.ContainsRune(`";,\`, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Header_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, "csv")
+	})
+	if err := m.Handle(http.MethodGet, "http://{tenant}.example.com/reports/{name}?year={year}", handler,
+		mux.Header("content-disposition", "attachment; filename={tenant}-{name}-{year}.csv"),
+		mux.Header("Content-Type", "text/csv"),
+		mux.Header("Cache-Control", "max-age=60"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://acme.example.com/reports/q1%20sales?year=2026", nil))
+	got := fmt.Sprint(rr.Header().Get("Content-Disposition"), "|", rr.Header().Get("Content-Type"), "|", rr.Header().Get("Cache-Control"))
+	if want := "attachment; filename=acme-q1 sales-2026.csv|text/csv|no-store"; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Header_failHostileValues(t *testing.T) {
+	m := &mux.Mux{}
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/reports/{name}", handler, mux.Header("Content-Disposition", "attachment; filename={name}")); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/reports/x.csv%3B%20filename=evil.exe", "/reports/x%22.csv", "/reports/x%0D%0ASet-Cookie:%20a=b", "/reports/a,b.csv"} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		if rr.Code != http.StatusBadRequest || called || rr.Header().Get("Content-Disposition") != "" {
+			t.Fatalf("path=%q, want=400 without the header, got=%d %q", path, rr.Code, rr.Header().Get("Content-Disposition"))
+		}
+	}
+}
+
+func TestMux_Header_failHeaderTemplateMustBeValid(t *testing.T) {
+	tests := []struct {
+		name, value string
+	}{
+		{"", "value"},
+		{"Bad Name", "value"},
+		{"Content-Disposition", "filename={name.csv"},
+		{"Content-Disposition", "filename=name}.csv"},
+		{"Content-Disposition", "filename={}.csv"},
+		{"Content-Disposition", "filename={missing}.csv"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{}
+		if err := m.Handle(http.MethodGet, "http://localhost/reports/{name}", http.HandlerFunc(emptyHandler), mux.Header(test.name, test.value)); err != mux.ErrHeaderTemplateMustBeValid {
+			t.Fatalf("name=%q, value=%q, expected: mux.ErrHeaderTemplateMustBeValid, got=%v", test.name, test.value, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+)
+
+//EarlyHints configures `Link` header values (Eg: "</style.css>; rel=preload; as=style") sent in a 103 Early Hints response before the route handler is called.
+//
+//Early hints are only sent to HTTP/2 and later clients, as many HTTP/1.1 clients do not handle informational responses.
+//The links are kept in the header of the final response.
+func EarlyHints(links ...string) RouteOption {
+	return func(o *routeOptions) error {
+		o.earlyHints = append(o.earlyHints, links...)
+		return nil
+	}
+}
+
+//earlyHints creates a handler that sends the early hints before calling the next one.
+func earlyHints(links []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor >= 2 {
+			for _, l := range links {
+				w.Header().Add("Link", l)
+			}
+			w.WriteHeader(http.StatusEarlyHints)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
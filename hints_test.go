@@ -0,0 +1,57 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type statusLogWriter struct {
+	*httptest.ResponseRecorder
+	statuses []int
+}
+
+func (w *statusLogWriter) WriteHeader(statusCode int) {
+	w.statuses = append(w.statuses, statusCode)
+	w.ResponseRecorder.WriteHeader(statusCode)
+}
+
+func TestMux_EarlyHints_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "https://localhost/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), mux.EarlyHints("</style.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, protoMajor := range []int{1, 2} {
+		req := httptest.NewRequest(http.MethodGet, "https://localhost/", nil)
+		req.ProtoMajor = protoMajor
+		w := &statusLogWriter{ResponseRecorder: httptest.NewRecorder()}
+		m.ServeHTTP(w, req)
+		want := "[200] []"
+		if protoMajor == 2 {
+			want = "[103 200] [</style.css>; rel=preload; as=style </app.js>; rel=preload; as=script]"
+		}
+		if got := fmt.Sprint(w.statuses, " ", w.Header()["Link"]); want != got {
+			t.Fatalf("proto=%d, want=%q, got=%q", protoMajor, want, got)
+		}
+	}
+}
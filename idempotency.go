@@ -0,0 +1,209 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//Errors returned by the Idempotency option.
+var (
+	//ErrIdempotencyMustBeValid is returned by Handle method when the Idempotency option receives a nil store or a non positive TTL, the IdempotencyScope option a nil scope or no previous Idempotency option, or the route method is not POST or PATCH.
+	ErrIdempotencyMustBeValid = errors.New("mux: invalid idempotency store, TTL or method")
+)
+
+//IdempotentResponse is a response kept by an IdempotencyStore to be replayed.
+type IdempotentResponse struct {
+	//Fingerprint identifies the original request (its method and absolute URL), so a key reused for a different request is detected.
+	Fingerprint string
+	//Status is the response HTTP status code.
+	Status int
+	//Header contains the response headers.
+	Header http.Header
+	//Body contains the response body.
+	Body []byte
+}
+
+//IdempotencyStore keeps the responses of idempotent requests by their Idempotency-Key, prefixed by a hash of the client. Eg: in memory or in a shared cache, for many instances.
+//
+//Load and Store are called concurrently.
+type IdempotencyStore interface {
+	//Load returns the response stored for the key, if it is not expired.
+	Load(key string) (IdempotentResponse, bool)
+	//Store keeps the response for the key until the ttl expires.
+	Store(key string, resp IdempotentResponse, ttl time.Duration)
+}
+
+//Idempotency makes a POST or PATCH route replay the previous response to requests repeating an Idempotency-Key header within the ttl, instead of calling the handler again.
+//Eg: m.Handle("POST", "https://api.example.com/payments", h, mux.Idempotency(&mux.MemoryIdempotencyStore{}, 24*time.Hour)).
+//
+//Requests without the header are served normally. Replayed responses have an "Idempotent-Replayed: true" header.
+//Keys are scoped by client (See IdempotencyScope), so the same key sent by different clients identifies different requests.
+//A key reused for a different request URL is answered with http.StatusUnprocessableEntity, and a key whose request is still being served by this Mux with http.StatusConflict.
+//Responses with server errors (5xx) are not stored, so the request can be retried.
+//
+//The response is buffered to be stored, so the option is ignored by Streaming routes.
+//
+//Errors
+//
+//• mux.ErrIdempotencyMustBeValid
+func Idempotency(store IdempotencyStore, ttl time.Duration) RouteOption {
+	return func(o *routeOptions) error {
+		if store == nil || ttl <= 0 {
+			return ErrIdempotencyMustBeValid
+		}
+		o.idempotency = &idempotency{store: store, ttl: ttl, inFlight: map[string]bool{}, scope: idempotencyClient}
+		return nil
+	}
+}
+
+//IdempotencyScope replaces how the Idempotency option identifies the client of a request, whose keys are kept apart from the keys of other clients.
+//Eg: mux.IdempotencyScope(func(r *http.Request) string { return r.Header.Get("X-Tenant") + " " + userFromSession(r) }).
+//
+//By default, clients are identified by their Authorization header and verified client certificate.
+//It must follow the Idempotency option.
+//
+//Errors
+//
+//• mux.ErrIdempotencyMustBeValid
+func IdempotencyScope(scope func(r *http.Request) string) RouteOption {
+	return func(o *routeOptions) error {
+		if scope == nil || o.idempotency == nil {
+			return ErrIdempotencyMustBeValid
+		}
+		o.idempotency.scope = scope
+		return nil
+	}
+}
+
+//idempotency replays the responses of a route by Idempotency-Key.
+type idempotency struct {
+	store    IdempotencyStore
+	ttl      time.Duration
+	lock     sync.Mutex
+	inFlight map[string]bool
+	//scope identifies the client of a request. Defaults to idempotencyClient.
+	scope func(r *http.Request) string
+}
+
+//wrap creates a handler that replays stored responses or stores the responses of the next one.
+func (id *idempotency) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		//Keys are scoped by client, so a client can not replay the responses of another one.
+		client := sha256.Sum256([]byte(id.scope(r)))
+		key = hex.EncodeToString(client[:]) + ":" + key
+		fingerprint := r.Method + " " + requestURL(r)
+
+		//Only one request by key is served at a time. The key is claimed before the store is looked up, so a response stored meanwhile is never missed.
+		id.lock.Lock()
+		if id.inFlight[key] {
+			id.lock.Unlock()
+			renderError(w, r, http.StatusConflict, "A request with the same Idempotency-Key is being processed.")
+			return
+		}
+		id.inFlight[key] = true
+		id.lock.Unlock()
+		defer func() {
+			id.lock.Lock()
+			delete(id.inFlight, key)
+			id.lock.Unlock()
+		}()
+
+		if resp, found := id.store.Load(key); found {
+			if resp.Fingerprint != fingerprint {
+				renderError(w, r, http.StatusUnprocessableEntity, "The Idempotency-Key was used by another request.")
+				return
+			}
+			for name, values := range resp.Header {
+				w.Header()[name] = values
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(resp.Status)
+			w.Write(resp.Body)
+			return
+		}
+
+		bw := &bufferWriter{header: http.Header{}}
+		next.ServeHTTP(bw, r)
+		status := bw.statusCode()
+		if status < 500 {
+			id.store.Store(key, IdempotentResponse{Fingerprint: fingerprint, Status: status, Header: bw.header.Clone(), Body: bw.body.Bytes()}, id.ttl)
+		}
+		for name, values := range bw.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(status)
+		w.Write(bw.body.Bytes())
+	})
+}
+
+//idempotencyClient identifies the client of a request by its Authorization header and its verified client certificate.
+func idempotencyClient(r *http.Request) string {
+	client := r.Header.Get("Authorization")
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		client += "\n" + string(r.TLS.PeerCertificates[0].Raw)
+	}
+	return client
+}
+
+//MemoryIdempotencyStore is an in-memory IdempotencyStore, for a single instance. Expired responses are discarded when new ones are stored.
+type MemoryIdempotencyStore struct {
+	lock    sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+//idempotencyEntry is a response kept by MemoryIdempotencyStore with its expiration time.
+type idempotencyEntry struct {
+	resp    IdempotentResponse
+	expires time.Time
+}
+
+//Load implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Load(key string) (IdempotentResponse, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, found := s.entries[key]
+	if !found || !time.Now().Before(e.expires) {
+		return IdempotentResponse{}, false
+	}
+	return e.resp, true
+}
+
+//Store implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Store(key string, resp IdempotentResponse, ttl time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	if s.entries == nil {
+		s.entries = map[string]idempotencyEntry{}
+	}
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = idempotencyEntry{resp: resp, expires: now.Add(ttl)}
+}
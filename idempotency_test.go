@@ -0,0 +1,265 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Idempotency_success(t *testing.T) {
+	m := &mux.Mux{}
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Location", fmt.Sprintf("/payments/%d", calls))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "payment %d", calls)
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", h, mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	post := func(key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil)
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		return w
+	}
+	if w := post("k1"); w.Code != http.StatusCreated || w.Body.String() != "payment 1" || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("unexpected first response: %d %q", w.Code, w.Body.String())
+	}
+	w := post("k1")
+	if w.Code != http.StatusCreated || w.Body.String() != "payment 1" || w.Header().Get("Location") != "/payments/1" || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected a replay, got: %d %q", w.Code, w.Body.String())
+	}
+	if w := post("k2"); w.Body.String() != "payment 2" {
+		t.Fatalf("expected: payment 2, got: %q", w.Body.String())
+	}
+	if w := post(""); w.Body.String() != "payment 3" {
+		t.Fatalf("expected: payment 3, got: %q", w.Body.String())
+	}
+	if calls != 3 {
+		t.Fatalf("expected: 3 calls, got: %d", calls)
+	}
+}
+
+func TestMux_Idempotency_keyReused(t *testing.T) {
+	m := &mux.Mux{}
+	store := &mux.MemoryIdempotencyStore{}
+	if err := m.Handle(http.MethodPost, "http://localhost/payments/{id}", http.HandlerFunc(emptyHandler), mux.Idempotency(store, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "http://localhost/payments/1", nil)
+	r.Header.Set("Idempotency-Key", "k")
+	m.ServeHTTP(httptest.NewRecorder(), r)
+	r = httptest.NewRequest(http.MethodPost, "http://localhost/payments/2", nil)
+	r.Header.Set("Idempotency-Key", "k")
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected: %d, got: %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestMux_Idempotency_inFlight(t *testing.T) {
+	m := &mux.Mux{}
+	started, done := make(chan struct{}), make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-done
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", h, mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil)
+		r.Header.Set("Idempotency-Key", "k")
+		return r
+	}
+	go m.ServeHTTP(httptest.NewRecorder(), newRequest())
+	<-started
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, newRequest())
+	close(done)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected: %d, got: %d", http.StatusConflict, w.Code)
+	}
+}
+
+//blockingStore is an IdempotencyStore whose first Load waits until released.
+type blockingStore struct {
+	mux.MemoryIdempotencyStore
+	loading, release chan struct{}
+	loads            int32
+}
+
+func (s *blockingStore) Load(key string) (mux.IdempotentResponse, bool) {
+	if atomic.AddInt32(&s.loads, 1) == 1 {
+		close(s.loading)
+		<-s.release
+	}
+	return s.MemoryIdempotencyStore.Load(key)
+}
+
+func TestMux_Idempotency_inFlightWhileLoading(t *testing.T) {
+	m := &mux.Mux{}
+	var calls int32
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	})
+	store := &blockingStore{loading: make(chan struct{}), release: make(chan struct{})}
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", h, mux.Idempotency(store, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil)
+		r.Header.Set("Idempotency-Key", "k")
+		return r
+	}
+	done := make(chan struct{})
+	go func() {
+		m.ServeHTTP(httptest.NewRecorder(), newRequest())
+		close(done)
+	}()
+	<-store.loading
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, newRequest())
+	close(store.release)
+	<-done
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected: %d, got: %d", http.StatusConflict, w.Code)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), newRequest())
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected: 1 call, got: %d", n)
+	}
+}
+
+func TestMux_Idempotency_successScopedByClient(t *testing.T) {
+	m := &mux.Mux{}
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, "payment %d", calls)
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", h, mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	post := func(authorization string) string {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil)
+		r.Header.Set("Idempotency-Key", "k")
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+	if body := post("Bearer alice"); body != "payment 1" {
+		t.Fatalf("expected: payment 1, got: %q", body)
+	}
+	if body := post("Bearer bob"); body != "payment 2" {
+		t.Fatalf("expected: payment 2, got: %q", body)
+	}
+	if body := post("Bearer alice"); body != "payment 1" {
+		t.Fatalf("expected a replay of payment 1, got: %q", body)
+	}
+}
+
+func TestMux_IdempotencyScope_success(t *testing.T) {
+	m := &mux.Mux{}
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	scope := func(r *http.Request) string {
+		return r.Header.Get("X-User")
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", h, mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour), mux.IdempotencyScope(scope)); err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []string{"alice", "bob", "alice"} {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil)
+		r.Header.Set("Idempotency-Key", "k")
+		r.Header.Set("X-User", user)
+		m.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if calls != 2 {
+		t.Fatalf("expected: 2 calls, got: %d", calls)
+	}
+}
+
+func TestMux_IdempotencyScope_failIdempotencyMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour), mux.IdempotencyScope(nil)); err != mux.ErrIdempotencyMustBeValid {
+		t.Fatalf("expected: %v, got: %v", mux.ErrIdempotencyMustBeValid, err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/b", http.HandlerFunc(emptyHandler), mux.IdempotencyScope(idempotencyTestScope)); err != mux.ErrIdempotencyMustBeValid {
+		t.Fatalf("expected: %v, got: %v", mux.ErrIdempotencyMustBeValid, err)
+	}
+}
+
+func idempotencyTestScope(r *http.Request) string {
+	return ""
+}
+
+func TestMux_Idempotency_serverErrorNotStored(t *testing.T) {
+	m := &mux.Mux{}
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", h, mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil)
+		r.Header.Set("Idempotency-Key", "k")
+		m.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if calls != 2 {
+		t.Fatalf("expected: 2 calls, got: %d", calls)
+	}
+}
+
+func TestMux_Idempotency_expired(t *testing.T) {
+	store := &mux.MemoryIdempotencyStore{}
+	store.Store("k", mux.IdempotentResponse{Status: http.StatusOK}, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, found := store.Load("k"); found {
+		t.Fatal("expected: expired response")
+	}
+}
+
+func TestMux_Idempotency_failIdempotencyMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.Idempotency(nil, time.Hour)); err != mux.ErrIdempotencyMustBeValid {
+		t.Fatalf("expected: mux.ErrIdempotencyMustBeValid, got: %v", err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.Idempotency(&mux.MemoryIdempotencyStore{}, 0)); err != mux.ErrIdempotencyMustBeValid {
+		t.Fatalf("expected: mux.ErrIdempotencyMustBeValid, got: %v", err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.Idempotency(&mux.MemoryIdempotencyStore{}, time.Hour)); err != mux.ErrIdempotencyMustBeValid {
+		t.Fatalf("expected: mux.ErrIdempotencyMustBeValid, got: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+)
+
+//Errors returned by the MaxContentLength option.
+var (
+	//ErrMaxContentLengthMustBeValid is returned by Handle method when the MaxContentLength option receives a size that is not positive.
+	ErrMaxContentLengthMustBeValid = errors.New("mux: max content length must be positive")
+)
+
+//RequireContentLength makes the route require a body with a declared length: requests without a positive Content-Length
+//(Eg: empty or chunked bodies) are answered with http.StatusLengthRequired before the handler is called.
+func RequireContentLength() RouteOption {
+	return func(o *routeOptions) error {
+		o.requireContentLength = true
+		return nil
+	}
+}
+
+//MaxContentLength limits the route request bodies to max bytes, as transmitted (before DecompressBody).
+//Requests declaring a greater Content-Length are answered with http.StatusRequestEntityTooLarge before the handler is called,
+//and reading a chunked body beyond max fails with a `*http.MaxBytesError`.
+//
+//Errors
+//
+//• mux.ErrMaxContentLengthMustBeValid
+func MaxContentLength(max int64) RouteOption {
+	return func(o *routeOptions) error {
+		if max <= 0 {
+			return ErrMaxContentLengthMustBeValid
+		}
+		o.maxContentLength = max
+		return nil
+	}
+}
+
+//checkContentLength creates a handler that rejects requests not respecting the declared length constraints before calling the next one.
+func checkContentLength(required bool, max int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if required && r.ContentLength <= 0 {
+			renderError(w, r, http.StatusLengthRequired, "The request must have a body with a declared Content-Length.")
+			return
+		}
+		if max > 0 && r.ContentLength > max {
+			renderError(w, r, http.StatusRequestEntityTooLarge, "The request body is too large.")
+			return
+		}
+		if max > 0 && r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,75 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ContentLength_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			fmt.Fprint(w, "too large")
+			return
+		}
+		fmt.Fprint(w, string(b))
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/required", handler, mux.RequireContentLength(), mux.MaxContentLength(5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/limited", handler, mux.MaxContentLength(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, body    string
+		contentLength int64
+		want          string
+	}{
+		{"/required", "hello", 5, "200 hello"},
+		{"/required", "", 0, "411 Length Required\n"},
+		{"/required", "hello", -1, "411 Length Required\n"},
+		{"/required", "hello world", 11, "413 Request Entity Too Large\n"},
+		{"/limited", "", 0, "200 "},
+		{"/limited", "hello world", -1, "200 too large"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost"+test.path, strings.NewReader(test.body))
+		req.ContentLength = test.contentLength
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Body.String()); test.want != got {
+			t.Fatalf("%s %q: want=%q, got=%q", test.path, test.body, test.want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failMaxContentLengthMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/", http.HandlerFunc(emptyHandler), mux.MaxContentLength(0)); err != mux.ErrMaxContentLengthMustBeValid {
+		t.Fatal("expected: mux.ErrMaxContentLengthMustBeValid")
+	}
+}
@@ -0,0 +1,54 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+//RouteLifecycle is an optional interface of handlers notified when their routes go live or are removed. Eg: to open database pools or pre-warm caches.
+//
+//The methods are called once per route (a handler registered in many routes is notified many times), outside of the routing table lock,
+//so they can use the Mux.
+type RouteLifecycle interface {
+	//OnRegister is called by Handle (and the other registering methods) before the route goes live.
+	//If it returns an error, the route is not registered and the error is returned by Handle.
+	OnRegister(route RouteInfo) error
+	//OnRemove is called by RemoveHandler after the route is removed.
+	//It is also called when a registration fails after OnRegister succeeded (Eg: due to a conflict), so the handler can release its resources.
+	OnRemove(route RouteInfo)
+}
+
+//registerHandlers calls OnRegister of the entry handlers implementing RouteLifecycle.
+//If any of them fails, the already registered ones are removed.
+func registerHandlers(entries []muxEntry) error {
+	for i, e := range entries {
+		l, ok := e.handler.(RouteLifecycle)
+		if !ok {
+			continue
+		}
+		if err := l.OnRegister(newRouteInfo(e)); err != nil {
+			removeHandlers(entries[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+//removeHandlers calls OnRemove of the entry handlers implementing RouteLifecycle, in reverse order.
+func removeHandlers(entries []muxEntry) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if l, ok := entries[i].handler.(RouteLifecycle); ok {
+			l.OnRemove(newRouteInfo(entries[i]))
+		}
+	}
+}
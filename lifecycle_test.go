@@ -0,0 +1,70 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type lifecycleHandler struct {
+	events      []string
+	registerErr error
+}
+
+func (h *lifecycleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (h *lifecycleHandler) OnRegister(route mux.RouteInfo) error {
+	h.events = append(h.events, "register "+route.String())
+	return h.registerErr
+}
+
+func (h *lifecycleHandler) OnRemove(route mux.RouteInfo) {
+	h.events = append(h.events, "remove "+route.String())
+}
+
+func TestMux_RouteLifecycle_success(t *testing.T) {
+	m := &mux.Mux{}
+	h := &lifecycleHandler{}
+	if err := m.Handle(http.MethodGet, "http://localhost/reports", h); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/reports", h); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/reports"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[register GET+http://localhost/reports register GET+http://localhost/reports remove GET+http://localhost/reports remove GET+http://localhost/reports]"
+	if got := fmt.Sprint(h.events); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_RouteLifecycle_failOnRegister(t *testing.T) {
+	m := &mux.Mux{}
+	errWarmUp := errors.New("database unavailable")
+	if err := m.Handle(http.MethodGet, "http://localhost/reports", &lifecycleHandler{registerErr: errWarmUp}); err != errWarmUp {
+		t.Fatalf("want=%v, got=%v", errWarmUp, err)
+	}
+	if want, got := 0, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
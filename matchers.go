@@ -0,0 +1,230 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"mime"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//Errors returned by matcher options.
+var (
+	//ErrProtoMajorMustBeValid is returned by Handle and RemoveHandler methods when the ProtoMajor option is not 1, 2 or 3.
+	ErrProtoMajorMustBeValid = errors.New("mux: HTTP major version must be 1, 2 or 3")
+	//ErrUserAgentMustBeValid is returned by Handle and RemoveHandler methods when the UserAgent option is empty or has an invalid regular expression.
+	ErrUserAgentMustBeValid = errors.New("mux: invalid User-Agent matcher")
+	//ErrContentTypeMustBeValid is returned by Handle and RemoveHandler methods when the ContentTypes option receives no media types or a media type that is not type/subtype (or type/*), without parameters.
+	ErrContentTypeMustBeValid = errors.New("mux: invalid Content-Type matcher")
+)
+
+//ProtoMajor is a matcher option that constrains a route to requests of a HTTP major version (`*http.Request.ProtoMajor`). Eg: 2 for h2-only endpoints.
+//
+//Matchers are part of the route identity: Routes that differ only by matchers do not conflict, and requests not matching a constrained route fall through to the next candidate (or NotFoundHandler).
+//
+//Errors
+//
+//• mux.ErrProtoMajorMustBeValid
+func ProtoMajor(major int) RouteOption {
+	return func(o *routeOptions) error {
+		if major < 1 || major > 3 {
+			return ErrProtoMajorMustBeValid
+		}
+		o.protoMajor = major
+		return nil
+	}
+}
+
+//UserAgent is a matcher option that constrains a route to requests whose User-Agent header contains the pattern.
+//Patterns inside slashes are regular expressions. Eg: mux.UserAgent("Googlebot") or mux.UserAgent("/(?i)bot|crawler|spider/").
+//
+//So known bots can be routed to a lightweight pre-rendered handler while browsers hit the application, registering both on the same route.
+//As any matcher, it is part of the route identity and unmatched requests fall through to the next candidate (or NotFoundHandler). See `mux.ProtoMajor`.
+//Routes with different User-Agent patterns are tested in the alphabetical order of their patterns.
+//
+//Errors
+//
+//• mux.ErrUserAgentMustBeValid
+func UserAgent(pattern string) RouteOption {
+	return func(o *routeOptions) error {
+		if pattern == "" {
+			return ErrUserAgentMustBeValid
+		}
+		m := &userAgentMatcher{pattern: pattern}
+		if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return ErrUserAgentMustBeValid
+			}
+			m.re = re
+		}
+		o.userAgent = m
+		return nil
+	}
+}
+
+//userAgentMatcher tests a User-Agent header against a substring or a regular expression.
+type userAgentMatcher struct {
+	pattern string
+	//re is used by regular expression patterns.
+	re *regexp.Regexp
+}
+
+//match tests a User-Agent header. A nil matcher accepts any header.
+func (m *userAgentMatcher) match(userAgent string) bool {
+	switch {
+	case m == nil:
+		return true
+	case m.re != nil:
+		return m.re.MatchString(userAgent)
+	}
+	return strings.Contains(userAgent, m.pattern)
+}
+
+//String returns the pattern. A nil matcher has an empty pattern.
+func (m *userAgentMatcher) String() string {
+	if m == nil {
+		return ""
+	}
+	return m.pattern
+}
+
+//patterns returns the pattern as a set, so it can be compared by compareOptionalStrings. A nil matcher has an empty set.
+func (m *userAgentMatcher) patterns() []string {
+	if m == nil {
+		return nil
+	}
+	return []string{m.pattern}
+}
+
+//ContentTypes is a matcher option that constrains a route to requests whose Content-Type header media type is one of the media types.
+//Media types are compared case-insensitively, without parameters (Eg: charset or boundary), and "type/*" matches any subtype.
+//Eg: mux.ContentTypes("multipart/form-data") and mux.ContentTypes("application/json") on two routes of the same upload endpoint.
+//
+//Routes with fewer "type/*" media types are tested first, so mux.ContentTypes("application/json") is not shadowed by mux.ContentTypes("application/*"). Routes still shadowed are reported by Unreachable method.
+//
+//As any matcher, it is part of the route identity and unmatched requests fall through to the next candidate (or NotFoundHandler). See `mux.ProtoMajor`.
+//
+//Errors
+//
+//• mux.ErrContentTypeMustBeValid
+func ContentTypes(mediaTypes ...string) RouteOption {
+	return func(o *routeOptions) error {
+		if len(mediaTypes) == 0 {
+			return ErrContentTypeMustBeValid
+		}
+		types := make([]string, len(mediaTypes))
+		for i, mt := range mediaTypes {
+			parsed, params, err := mime.ParseMediaType(mt)
+			if err != nil || len(params) > 0 || strings.Count(parsed, "/") != 1 || strings.HasPrefix(parsed, "*") || strings.HasSuffix(parsed, "/") {
+				return ErrContentTypeMustBeValid
+			}
+			types[i] = parsed
+		}
+		sort.Strings(types)
+		o.contentTypes = types
+		return nil
+	}
+}
+
+//acceptsContentType tests the request Content-Type media type against the route media types.
+func (r *muxRoute) acceptsContentType(rm *requestMatch) bool {
+	mt, _, err := mime.ParseMediaType(rm.req.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, t := range r.contentTypes {
+		if mediaTypeMatches(t, mt) {
+			return true
+		}
+	}
+	return false
+}
+
+//mediaTypeMatches tests if a route media type matches a media type. A "type/*" route media type matches any subtype, even a "type/*" one.
+func mediaTypeMatches(routeType, mediaType string) bool {
+	return routeType == mediaType || (strings.HasSuffix(routeType, "/*") && strings.HasPrefix(mediaType, routeType[:len(routeType)-1]))
+}
+
+//mediaTypesCover tests if the route media types match every one of the other route media types.
+func mediaTypesCover(types, other []string) bool {
+	for _, o := range other {
+		covered := false
+		for _, t := range types {
+			covered = covered || mediaTypeMatches(t, o)
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+//compareContentTypes compares the media types of two routes, as compareOptionalStrings does,
+//but sorting the sets with fewer "type/*" media types first, so exact media types are tested before the wildcards that would shadow them.
+func compareContentTypes(t1, t2 []string) int {
+	if len(t1) > 0 && len(t2) > 0 {
+		if r := countWildcards(t1) - countWildcards(t2); r != 0 {
+			return r
+		}
+	}
+	return compareOptionalStrings(t1, t2)
+}
+
+//countWildcards counts the "type/*" media types.
+func countWildcards(types []string) int {
+	n := 0
+	for _, t := range types {
+		if strings.HasSuffix(t, "/*") {
+			n++
+		}
+	}
+	return n
+}
+
+//HideMethods makes requests to the route path with a method not registered answered by NotFoundHandler (404 status) instead of a 405 status,
+//so public sites do not reveal which methods exist. Internal APIs usually keep the REST-correct 405 status, with an Allow header listing the path methods.
+//
+//It is decided per path: If any route of the path has this option, method mismatches are not found. Use a Group per host to apply it to a whole host.
+func HideMethods() RouteOption {
+	return func(o *routeOptions) error {
+		o.hideMethods = true
+		return nil
+	}
+}
+
+//hideMethods tests if any entry hides its methods.
+func (entries muxEntries) hideMethods() bool {
+	for _, e := range entries {
+		if e.options.hideMethods {
+			return true
+		}
+	}
+	return false
+}
+
+//methods lists the distinct methods of sorted entries.
+func (entries muxEntries) methods() []string {
+	methods := []string{}
+	for _, e := range entries {
+		if len(methods) == 0 || methods[len(methods)-1] != e.route.method {
+			methods = append(methods, e.route.method)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,264 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ProtoMajor_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "https://localhost/rpc", newTestHandler("h2"), mux.ProtoMajor(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "https://localhost/rpc", newTestHandler("any")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "https://localhost/grpc", newTestHandler("h2 only"), mux.ProtoMajor(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path       string
+		protoMajor int
+		wantStatus int
+		wantBody   string
+	}{
+		{"/rpc", 2, http.StatusOK, "h2"},
+		{"/rpc", 1, http.StatusOK, "any"},
+		{"/grpc", 2, http.StatusOK, "h2 only"},
+		{"/grpc", 1, http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "https://localhost"+test.path, nil)
+		req.ProtoMajor = test.protoMajor
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := test.wantStatus, rr.Code; want != got {
+			t.Fatalf("path=%q, proto=%d, want=%d, got=%d", test.path, test.protoMajor, want, got)
+		}
+		if want, got := test.wantBody, rr.Body.String(); want != got {
+			t.Fatalf("path=%q, proto=%d, want=%q, got=%q", test.path, test.protoMajor, want, got)
+		}
+	}
+
+	if err := m.Handle(http.MethodPost, "https://localhost/rpc", http.HandlerFunc(emptyHandler), mux.ProtoMajor(2)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := m.RemoveHandler(http.MethodPost, "https://localhost/grpc"); err != mux.ErrRouteMustExist {
+		t.Fatal("expected: mux.ErrRouteMustExist")
+	}
+	if err := m.RemoveHandler(http.MethodPost, "https://localhost/grpc", mux.ProtoMajor(2)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMux_Handle_failProtoMajorMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.ProtoMajor(0)); err != mux.ErrProtoMajorMustBeValid {
+		t.Fatal("expected: mux.ErrProtoMajorMustBeValid")
+	}
+}
+
+type testGeoResolver map[string]string
+
+func (g testGeoResolver) Country(ip net.IP) (string, error) {
+	country, ok := g[ip.String()]
+	if !ok {
+		return "", errors.New("unknown IP")
+	}
+	return country, nil
+}
+
+func TestMux_Countries_success(t *testing.T) {
+	m := &mux.Mux{GeoResolver: testGeoResolver{"192.0.2.1": "br", "192.0.2.2": "US", "192.0.2.3": "KP"}}
+	if err := m.Handle(http.MethodGet, "http://localhost/video", newTestHandler("licensed"), mux.Countries("BR", "pt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/video", newTestHandler("trailer"), mux.ExceptCountries("KP")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		remoteAddr string
+		want       string
+	}{
+		{"192.0.2.1:1234", "200 licensed"},
+		{"192.0.2.2:1234", "200 trailer"},
+		{"192.0.2.3:1234", "404 404 page not found\n"},
+		{"192.0.2.4:1234", "200 trailer"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/video", nil)
+		req.RemoteAddr = test.remoteAddr
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Body.String()); test.want != got {
+			t.Fatalf("remoteAddr=%q, want=%q, got=%q", test.remoteAddr, test.want, got)
+		}
+	}
+
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/video", mux.Countries("PT", "BR")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMux_Handle_failCountryMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Countries("BRA")); err != mux.ErrCountryMustBeValid {
+		t.Fatal("expected: mux.ErrCountryMustBeValid")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.ExceptCountries()); err != mux.ErrCountryMustBeValid {
+		t.Fatal("expected: mux.ErrCountryMustBeValid")
+	}
+}
+
+func TestMux_UserAgent_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", newTestHandler("pre-rendered"), mux.UserAgent("/(?i)bot|crawler/")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", newTestHandler("preview"), mux.UserAgent("Slack")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", newTestHandler("spa")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"Mozilla/5.0 (compatible; Googlebot/2.1)": "pre-rendered",
+		"Slackbot-LinkExpanding 1.0":              "pre-rendered",
+		"Slack-ImgProxy":                          "preview",
+		"Mozilla/5.0 (X11; Linux x86_64)":         "spa",
+	}
+	for userAgent, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.Header.Set("User-Agent", userAgent)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("userAgent=%q, want=%q, got=%q", userAgent, want, got)
+		}
+	}
+
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.UserAgent("Slack")); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+}
+
+func TestMux_Handle_failUserAgentMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.UserAgent("/(/")); err != mux.ErrUserAgentMustBeValid {
+		t.Fatal("expected: mux.ErrUserAgentMustBeValid")
+	}
+}
+
+func TestMux_ContentTypes_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/uploads", newTestHandler("form"), mux.ContentTypes("multipart/form-data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/uploads", newTestHandler("json"), mux.ContentTypes("application/json", "text/json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/uploads", newTestHandler("image"), mux.ContentTypes("image/*")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		contentType string
+		wantStatus  int
+		want        string
+	}{
+		{"multipart/form-data; boundary=xyz", http.StatusOK, "form"},
+		{"Application/JSON; charset=utf-8", http.StatusOK, "json"},
+		{"text/json", http.StatusOK, "json"},
+		{"image/png", http.StatusOK, "image"},
+		{"text/plain", http.StatusNotFound, "404 page not found\n"},
+		{"", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/uploads", nil)
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if rr.Code != test.wantStatus || rr.Body.String() != test.want {
+			t.Fatalf("contentType=%q, want=%d %q, got=%d %q", test.contentType, test.wantStatus, test.want, rr.Code, rr.Body.String())
+		}
+	}
+	for _, r := range m.ExampleRequests() {
+		if _, found := m.Match(r); !found {
+			t.Fatalf("example not matched: %v", r.Header)
+		}
+	}
+	if types := m.Routes()[0].ContentTypes; len(types) != 2 || types[0] != "application/json" {
+		t.Fatalf("unexpected ContentTypes: %v", types)
+	}
+}
+
+func TestMux_ContentTypes_successExactBeforeWildcard(t *testing.T) {
+	//The wildcard must not shadow the exact media type, whatever the registration order.
+	for _, wildcardFirst := range []bool{true, false} {
+		m := &mux.Mux{}
+		specs := []mux.RouteSpec{
+			{Method: http.MethodPost, URLPattern: "http://localhost/uploads", Handler: newTestHandler("any"), Options: []mux.RouteOption{mux.ContentTypes("application/*")}},
+			{Method: http.MethodPost, URLPattern: "http://localhost/uploads", Handler: newTestHandler("json"), Options: []mux.RouteOption{mux.ContentTypes("application/json")}},
+		}
+		if !wildcardFirst {
+			specs[0], specs[1] = specs[1], specs[0]
+		}
+		for _, s := range specs {
+			if err := m.Handle(s.Method, s.URLPattern, s.Handler, s.Options...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for contentType, want := range map[string]string{"application/json": "json", "application/xml": "any"} {
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/uploads", nil)
+			req.Header.Set("Content-Type", contentType)
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, req)
+			if got := rr.Body.String(); want != got {
+				t.Fatalf("wildcardFirst=%v, contentType=%q, want=%q, got=%q", wildcardFirst, contentType, want, got)
+			}
+		}
+		if routes := m.Unreachable(); len(routes) != 0 {
+			t.Fatalf("unexpected unreachable routes: %v", routes)
+		}
+	}
+}
+
+func TestMux_ContentTypes_fail(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/uploads", http.HandlerFunc(emptyHandler), mux.ContentTypes("application/json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/uploads", http.HandlerFunc(emptyHandler), mux.ContentTypes("APPLICATION/json")); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatalf("expected: mux.ErrRouteMustNotConflict, got: %v", err)
+	}
+	for _, types := range [][]string{nil, {"json"}, {"*/*"}, {"text/plain; charset=utf-8"}, {"text/"}} {
+		if err := m.Handle(http.MethodPost, "http://localhost/uploads", http.HandlerFunc(emptyHandler), mux.ContentTypes(types...)); err != mux.ErrContentTypeMustBeValid {
+			t.Fatalf("types=%v, expected: mux.ErrContentTypeMustBeValid, got: %v", types, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"strings"
+)
+
+//AllowMethods makes extension HTTP methods (Eg: the WebDAV "PROPFIND", "MKCOL" and "REPORT") valid for Handle and RemoveHandler methods, besides the standard ones.
+//Eg: m.AllowMethods("PROPFIND", "PROPPATCH", "MKCOL") .
+//
+//Methods are case-sensitive tokens (RFC 7230). Extension methods are considered unsafe, so they are checked by SameOrigin option and reported by UnprotectedRoutes method.
+//
+//Errors
+//
+//• mux.ErrMethodMustBeValid
+func (m *Mux) AllowMethods(methods ...string) error {
+	for _, method := range methods {
+		if !validMethodToken(method) {
+			return ErrMethodMustBeValid
+		}
+	}
+	m.entriesLock.Lock()
+	defer m.entriesLock.Unlock()
+	for _, method := range methods {
+		if !containsString(defaultAllowedHTTPMethods, method) && !containsString(m.extensionMethods, method) {
+			m.extensionMethods = append(m.extensionMethods, method)
+		}
+	}
+	return nil
+}
+
+//allowsMethod tells if the method is a standard one or was allowed by AllowMethods method. The caller must hold entriesLock.
+func (m *Mux) allowsMethod(method string) bool {
+	return containsString(defaultAllowedHTTPMethods, method) || containsString(m.extensionMethods, method)
+}
+
+//isUnsafeMethod tells if a method may change the server state: the standard unsafe ones and any extension method.
+func isUnsafeMethod(method string) bool {
+	return containsString(unsafeHTTPMethods, method) || !containsString(defaultAllowedHTTPMethods, method)
+}
+
+//validMethodToken tells if the method is a non-empty RFC 7230 token.
+func validMethodToken(method string) bool {
+	if method == "" {
+		return false
+	}
+	for _, c := range method {
+		if c > '~' || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_AllowMethods_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle("PROPFIND", "http://localhost/dav/{file}", http.HandlerFunc(emptyHandler)); err != mux.ErrMethodMustBeValid {
+		t.Fatalf("expected: mux.ErrMethodMustBeValid, got: %v", err)
+	}
+	if err := m.AllowMethods("PROPFIND", "MKCOL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle("PROPFIND", "http://localhost/dav/{file}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(207)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("PROPFIND", "http://localhost/dav/a.txt", nil))
+	if w.Code != 207 {
+		t.Fatalf("expected: 207, got: %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("MKCOL", "http://localhost/dav/a.txt", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected: %d, got: %d", http.StatusMethodNotAllowed, w.Code)
+	}
+	if err := m.RemoveHandler("PROPFIND", "http://localhost/dav/{file}"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMux_AllowMethods_failMethodMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	for _, method := range []string{"", "PROP FIND", "MK/COL"} {
+		if err := m.AllowMethods(method); err != mux.ErrMethodMustBeValid {
+			t.Fatalf("%q expected: mux.ErrMethodMustBeValid, got: %v", method, err)
+		}
+	}
+	if err := m.RemoveHandler("REPORT", "http://localhost/dav/{file}"); err != mux.ErrMethodMustBeValid {
+		t.Fatalf("expected: mux.ErrMethodMustBeValid, got: %v", err)
+	}
+}
+
+func TestMux_AllowMethods_unprotected(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.AllowMethods("MKCOL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle("MKCOL", "http://localhost/dav/{dir}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if routes := m.UnprotectedRoutes(); len(routes) != 1 || routes[0].Method != "MKCOL" {
+		t.Fatalf("expected: the MKCOL route, got: %v", routes)
+	}
+}
@@ -0,0 +1,134 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+)
+
+//Errors returned by the Use option.
+var (
+	//ErrMiddlewareMustBeNotNil is returned by Handle method when the Use option receives a nil Middleware.
+	ErrMiddlewareMustBeNotNil = errors.New("mux: middleware must be not nil")
+	//ErrMiddlewareMustPrecedeRoutes is returned by Use method when routes are already registered, as their handlers are already wrapped.
+	ErrMiddlewareMustPrecedeRoutes = errors.New("mux: middleware must be added before routes are registered")
+)
+
+//Middleware wraps a handler, returning a handler that runs before (and after) it. Eg: authentication, logging or CORS.
+type Middleware func(http.Handler) http.Handler
+
+//GroupOptions defines the behavior of a Group created by GroupWith method.
+//
+//Middleware Ordering
+//
+//The handler of a route is wrapped by middleware in a fixed order, from the first to run to the last:
+//
+//• Mux.Middleware, in slice order. It is skipped by Exempt routes.
+//
+//• The middleware of the group options (set with the Use option), in option order, outer groups first.
+//
+//• The middleware of the route options.
+//
+//So global (Eg: security) middleware is guaranteed to run before group and route middleware, unless a group sets MiddlewareFirst.
+//The middleware runs before the per-route behaviors of the Mux (Eg: MaxConcurrent or RedirectSlash), and after the route is matched.
+type GroupOptions struct {
+	//MiddlewareFirst runs the middleware of the group (including its outer groups) before Mux.Middleware. Eg: a group of public health checks bypassing a global rate limiter.
+	MiddlewareFirst bool
+}
+
+//Use adds middleware to a route, running after Mux.Middleware and the middleware of previous options. See `mux.GroupOptions` for the ordering.
+//
+//Errors
+//
+//• mux.ErrMiddlewareMustBeNotNil
+func Use(mw ...Middleware) RouteOption {
+	return func(o *routeOptions) error {
+		for _, w := range mw {
+			if w == nil {
+				return ErrMiddlewareMustBeNotNil
+			}
+		}
+		o.middleware = append(o.middleware, mw...)
+		return nil
+	}
+}
+
+//Exempt opts a route out of the Mux-wide wrappers, so health checks and metrics are not logged, measured or rejected by them:
+//Mux.Middleware is not applied, the Mux.Observer is not notified and the route is not counted by Mux.AuditErrors and Mux.AuditBytes.
+//
+//It also drops the middleware (See Use option) and the limits (See MaxConcurrent and Queue options) set by previous options, Eg: inherited from groups.
+//Options following it still apply. Draining by Shutdown method is not affected: see ShutdownExempt and Readiness options.
+func Exempt() RouteOption {
+	return func(o *routeOptions) error {
+		o.exempt = true
+		o.middleware, o.firstMiddleware = nil, 0
+		o.maxConcurrent, o.queue = 0, nil
+		return nil
+	}
+}
+
+//Use appends middleware to Mux.Middleware, wrapping the handlers of all routes registered afterwards. The first middleware runs first.
+//See `mux.GroupOptions` for the ordering with group and route middleware (added by the Use option).
+//
+//Errors
+//
+//• mux.ErrMiddlewareMustBeNotNil
+//
+//• mux.ErrMiddlewareMustPrecedeRoutes
+func (m *Mux) Use(mw ...Middleware) error {
+	for _, w := range mw {
+		if w == nil {
+			return ErrMiddlewareMustBeNotNil
+		}
+	}
+	m.entriesLock.Lock()
+	defer m.entriesLock.Unlock()
+	if len(m.entries) > 0 {
+		return ErrMiddlewareMustPrecedeRoutes
+	}
+	m.Middleware = append(m.Middleware, mw...)
+	return nil
+}
+
+//markFirstMiddleware is added after the options of groups with MiddlewareFirst set, so the middleware added until it runs before Mux.Middleware.
+func markFirstMiddleware(o *routeOptions) error {
+	o.firstMiddleware = len(o.middleware)
+	return nil
+}
+
+//applyMiddleware wraps the chain of a new entry by the middleware of the Mux and of its options, and by the Audited option.
+func (m *Mux) applyMiddleware(e *muxEntry) {
+	first, last := e.options.middleware[:e.options.firstMiddleware], e.options.middleware[e.options.firstMiddleware:]
+	e.chain = wrapMiddleware(e.chain, last)
+	if !e.options.exempt {
+		e.chain = wrapMiddleware(e.chain, m.Middleware)
+	}
+	e.chain = wrapMiddleware(e.chain, first)
+	//Audit runs before every middleware, so requests rejected by them (Eg: by authentication) are audited too.
+	if e.options.auditor != nil {
+		e.chain = audit(e.options.auditor, newRouteInfo(*e), e.chain)
+	}
+}
+
+//wrapMiddleware wraps a handler so the first middleware runs first.
+func wrapMiddleware(h http.Handler, mw []Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
@@ -0,0 +1,164 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func newTraceMiddleware(name string) mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name + ">"))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMux_Middleware_success(t *testing.T) {
+	m := &mux.Mux{Middleware: []mux.Middleware{newTraceMiddleware("security"), newTraceMiddleware("log")}}
+	api := m.Group(mux.Use(newTraceMiddleware("api")))
+	v1 := api.Group(mux.Use(newTraceMiddleware("v1")))
+	health := m.GroupWith(mux.GroupOptions{MiddlewareFirst: true}, mux.Use(newTraceMiddleware("health")))
+	if err := v1.Handle(http.MethodGet, "http://localhost/api/v1/users", newTestHandler("users"), mux.Use(newTraceMiddleware("route"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := health.Handle(http.MethodGet, "http://localhost/health", newTestHandler("ok"), mux.Use(newTraceMiddleware("route"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", newTestHandler("root")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/api/v1/users", "security>log>api>v1>route>users"},
+		{"/health", "health>security>log>route>ok"},
+		{"/", "security>log>root"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil))
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failMiddlewareMustBeNotNil(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Use(nil)); err != mux.ErrMiddlewareMustBeNotNil {
+		t.Fatal("expected: mux.ErrMiddlewareMustBeNotNil")
+	}
+}
+
+func TestMux_Exempt_success(t *testing.T) {
+	o := &testObserver{}
+	m := &mux.Mux{Observer: o, Middleware: []mux.Middleware{newTraceMiddleware("security"), newTraceMiddleware("log")}}
+	api := m.Group(mux.Use(newTraceMiddleware("api")), mux.MaxConcurrent(1))
+	if err := api.Handle(http.MethodGet, "http://localhost/api/users", newTestHandler("users")); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.Handle(http.MethodGet, "http://localhost/api/health", newTestHandler("ok"), mux.Exempt(), mux.Use(newTraceMiddleware("route"))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/api/users", "security>log>api>users"},
+		{"/api/health", "route>ok"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil))
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+	if want, got := 2, len(o.events); want != got {
+		t.Fatalf("want=%d, got=%d: %v", want, got, o.events)
+	}
+
+	routes := m.Routes()
+	if want, got := "true false", fmt.Sprint(routes[0].Exempt, " ", routes[1].Exempt); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Exempt_successLimits(t *testing.T) {
+	m := &mux.Mux{}
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+	limited := m.Group(mux.MaxConcurrent(1))
+	if err := limited.Handle(http.MethodGet, "http://localhost/health", slow, mux.Exempt()); err != nil {
+		t.Fatal(err)
+	}
+
+	//Both requests are served, as the route is not limited.
+	done := make(chan int)
+	for i := 0; i < 2; i++ {
+		go func() {
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/health", nil))
+			done <- rr.Code
+		}()
+	}
+	<-started
+	<-started
+	close(release)
+	for i := 0; i < 2; i++ {
+		if want, got := http.StatusOK, <-done; want != got {
+			t.Fatalf("want=%d, got=%d", want, got)
+		}
+	}
+}
+
+func TestMux_Use_success(t *testing.T) {
+	m := &mux.Mux{Middleware: []mux.Middleware{newTraceMiddleware("recovery")}}
+	if err := m.Use(newTraceMiddleware("log"), newTraceMiddleware("auth")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", newTestHandler("orders"), mux.Use(newTraceMiddleware("route"))); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/orders", nil))
+	if want, got := "recovery>log>auth>route>orders", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Use_fail(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Use(nil); err != mux.ErrMiddlewareMustBeNotNil {
+		t.Fatal("expected: mux.ErrMiddlewareMustBeNotNil")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Use(newTraceMiddleware("log")); err != mux.ErrMiddlewareMustPrecedeRoutes {
+		t.Fatal("expected: mux.ErrMiddlewareMustPrecedeRoutes")
+	}
+}
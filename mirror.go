@@ -0,0 +1,129 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+)
+
+//Errors returned by the MirrorTo option.
+var (
+	//ErrMirrorMustBeValid is returned by Handle method when the MirrorTo option receives a nil Mirror, a Mirror without Handler or a Percent not between 0 and 100.
+	ErrMirrorMustBeValid = errors.New("mux: Mirror must have a Handler and a Percent between 0 and 100")
+)
+
+//Mirror sends a copy of the requests of the routes it is attached to by the MirrorTo option to a shadow handler (Eg: an experimental backend behind a `httputil.ReverseProxy`).
+//
+//Mirrored requests are served concurrently, detached from the client request context, and their responses are discarded.
+//Percent and MaxConcurrent keep shadow testing from overloading the experimental backend.
+//
+//The fields must not be changed after the Mirror is attached to a route.
+type Mirror struct {
+	//Handler serves the mirrored requests.
+	Handler http.Handler
+	//Percent is the percentage (between 0 and 100) of requests mirrored.
+	Percent float64
+	//MaxConcurrent limits the number of mirrored requests being served at the same time. Requests over the limit are not mirrored. Zero means unlimited.
+	MaxConcurrent int
+	//MaxBodySize is the maximum request body size mirrored. Requests with larger bodies are not mirrored. If zero, 64KiB is used.
+	MaxBodySize int
+	inFlight    int32
+}
+
+//MirrorTo attaches a Mirror to a route. Streaming routes are never mirrored.
+//
+//Errors
+//
+//• mux.ErrMirrorMustBeValid
+func MirrorTo(mi *Mirror) RouteOption {
+	return func(o *routeOptions) error {
+		if mi == nil || mi.Handler == nil || mi.Percent < 0 || mi.Percent > 100 {
+			return ErrMirrorMustBeValid
+		}
+		o.mirror = mi
+		return nil
+	}
+}
+
+//InFlight returns the number of mirrored requests being served.
+func (mi *Mirror) InFlight() int {
+	return int(atomic.LoadInt32(&mi.inFlight))
+}
+
+//wrap creates a handler that mirrors a copy of the request before calling the next one.
+func (mi *Mirror) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rand.Float64()*100 >= mi.Percent || !mi.acquire() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		mr, ok := mi.clone(r)
+		if !ok {
+			mi.release()
+			next.ServeHTTP(w, r)
+			return
+		}
+		go func() {
+			defer mi.release()
+			//A failing shadow handler must not crash the server.
+			defer func() { recover() }()
+			mi.Handler.ServeHTTP(&bufferWriter{header: http.Header{}}, mr)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+//acquire reserves a concurrency slot, returning false when MaxConcurrent is reached.
+func (mi *Mirror) acquire() bool {
+	if n := atomic.AddInt32(&mi.inFlight, 1); mi.MaxConcurrent > 0 && int(n) > mi.MaxConcurrent {
+		mi.release()
+		return false
+	}
+	return true
+}
+
+//release frees a concurrency slot.
+func (mi *Mirror) release() {
+	atomic.AddInt32(&mi.inFlight, -1)
+}
+
+//clone copies the request and its body for mirroring, returning false if the body is too large.
+func (mi *Mirror) clone(r *http.Request) (*http.Request, bool) {
+	mr := r.Clone(context.Background())
+	if r.Body == nil || r.Body == http.NoBody {
+		return mr, true
+	}
+	maxBodySize := mi.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = defaultMaxRecordedBodySize
+	}
+
+	//Read the body up to the limit. If it is larger, give the original body back to the handler.
+	body, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBodySize)+1))
+	if err != nil || len(body) > maxBodySize {
+		r.Body = &teeReadCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	mr.Body = io.NopCloser(bytes.NewReader(body))
+	return mr, true
+}
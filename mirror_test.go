@@ -0,0 +1,79 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_MirrorTo_success(t *testing.T) {
+	bodies := make(chan string, 2)
+	release := make(chan struct{})
+	mirror := &mux.Mirror{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies <- string(b)
+			<-release
+		}),
+		Percent:       100,
+		MaxConcurrent: 1,
+	}
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Write(b)
+	}), mux.MirrorTo(mirror)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{"first", "second"} {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/orders", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := body, rr.Body.String(); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+		if body == "first" {
+			if want, got := "first", <-bodies; want != got {
+				t.Fatalf("want=%q, got=%q", want, got)
+			}
+		}
+	}
+
+	//The second request exceeded MaxConcurrent, so it was not mirrored.
+	if want, got := 1, mirror.InFlight(); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+	close(release)
+	if len(bodies) != 0 {
+		t.Fatal("expected: a single mirrored request")
+	}
+}
+
+func TestMux_Handle_failMirrorMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.MirrorTo(&mux.Mirror{Percent: 10})); err != mux.ErrMirrorMustBeValid {
+		t.Fatal("expected: mux.ErrMirrorMustBeValid")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.MirrorTo(&mux.Mirror{Handler: http.HandlerFunc(emptyHandler), Percent: 101})); err != mux.ErrMirrorMustBeValid {
+		t.Fatal("expected: mux.ErrMirrorMustBeValid")
+	}
+}
@@ -0,0 +1,117 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Errors returned by Mount and HandlePrefix methods.
+var (
+	//ErrMountPatternMustBeValid is returned by Mount and HandlePrefix methods when the urlPattern parameter does not end with the {*} sub path variable.
+	ErrMountPatternMustBeValid = errors.New("mux: mount URL pattern must end with /{*}")
+)
+
+//Mount dispatches the requests matching urlPattern (Eg: https://host/api/{*}) into another Mux, after stripping the prefix from the request path, so route tables of independent modules can be composed.
+//
+//The sub Mux receives the sub path (Eg: /users/42 for https://host/api/users/42), so its routes are registered without the prefix (Eg: https://host/users/{id}).
+//Its PathVars method works as usual in its handlers, and so does the PathVars method of this Mux (Eg: for https://host/{version}/{*}).
+//
+//The prefix is registered for every method (including the extension methods already allowed by AllowMethods method), so 404 and 405 statuses are answered by the sub Mux.
+//The prefix itself (Eg: https://host/api) is not mounted, as {*} requires at least one segment. The opts apply to all mounted routes.
+//
+//Errors
+//
+//• mux.ErrMountPatternMustBeValid
+//
+//• mux.ErrMuxMustBeNotNil
+//
+//• Any error returned by HandleAll method.
+func (m *Mux) Mount(urlPattern string, sub *Mux, opts ...RouteOption) error {
+	if !validMountPattern(urlPattern) {
+		return ErrMountPatternMustBeValid
+	}
+	if sub == nil {
+		return ErrMuxMustBeNotNil
+	}
+	m.entriesLock.RLock()
+	methods := append(append([]string{}, defaultAllowedHTTPMethods...), m.extensionMethods...)
+	m.entriesLock.RUnlock()
+	h := &prefixHandler{mux: m, next: sub, name: "mux.Mount"}
+	specs := make([]RouteSpec, len(methods))
+	for i, method := range methods {
+		specs[i] = RouteSpec{Method: method, URLPattern: urlPattern, Handler: h, Options: opts}
+	}
+	return m.HandleAll(specs)
+}
+
+//HandlePrefix creates a routing entry whose handler receives the request with the matched prefix stripped from its path, so `http.FileServer` and third-party handlers work without `http.StripPrefix`.
+//Eg: for https://host/static/{*}, a request to https://host/static/css/site.css is handled with the /css/site.css path.
+//
+//Errors
+//
+//• mux.ErrMountPatternMustBeValid
+//
+//• Any error returned by Handle method.
+func (m *Mux) HandlePrefix(httpMethod string, urlPattern string, handler http.Handler, opts ...RouteOption) error {
+	if !validMountPattern(urlPattern) {
+		return ErrMountPatternMustBeValid
+	}
+	if handler == nil {
+		return ErrHandlerMustBeNotNil
+	}
+	return m.Handle(httpMethod, urlPattern, &prefixHandler{mux: m, next: handler, name: describeHandler(handler)}, opts...)
+}
+
+//validMountPattern tests if an URL pattern path ends with the {*} sub path variable.
+func validMountPattern(urlPattern string) bool {
+	if i := strings.Index(urlPattern, "?"); i != -1 {
+		urlPattern = urlPattern[:i]
+	}
+	return strings.HasSuffix(urlPattern, "/{*}")
+}
+
+//prefixHandler calls the next handler with the matched prefix stripped from the request path.
+type prefixHandler struct {
+	mux  *Mux
+	next http.Handler
+	//name describes the handler in RouteInfo.
+	name string
+}
+
+//ServeHTTP rewrites the request path to the {*} sub path, keeping the trailing slash.
+func (h *prefixHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := "/" + h.mux.pathVars(r, true)["*"]
+	if strings.HasSuffix(r.URL.EscapedPath(), "/") && path != "/" {
+		path += "/"
+	}
+	u := *r.URL
+	u.RawPath = path
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		u.Path = unescaped
+	}
+	r = r.WithContext(r.Context())
+	r.URL = &u
+	h.next.ServeHTTP(w, r)
+}
+
+//Describe describes the next handler.
+func (h *prefixHandler) Describe() string {
+	return h.name
+}
@@ -0,0 +1,118 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Mount_success(t *testing.T) {
+	m, users := &mux.Mux{}, &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path, " ", users.PathVars(r), " ", m.PathVars(r))
+	})
+	if err := users.Handle(http.MethodGet, "http://localhost/users/{id}", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Handle(http.MethodGet, "http://localhost/files/{*}", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Mount("http://localhost/api/{version}/{*}", users); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", newTestHandler("home")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, url, want string
+	}{
+		{http.MethodGet, "http://localhost/api/v1/users/42", "200 /users/42 map[id:42] map[*:users/42 version:v1]"},
+		{http.MethodGet, "http://localhost/api/v1/files/a%2Fb/c.txt", "200 /files/a/b/c.txt map[*:a%2Fb/c.txt] map[*:files/a%2Fb/c.txt version:v1]"},
+		{http.MethodPost, "http://localhost/api/v1/users/42", "405 "},
+		{http.MethodGet, "http://localhost/api/v1/orders", "404 "},
+		{http.MethodGet, "http://localhost/", "200 home"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(test.method, test.url, nil))
+		got := fmt.Sprint(rr.Code, " ")
+		if rr.Code == http.StatusOK {
+			got += rr.Body.String()
+		}
+		if test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+	routes := m.Routes()
+	if want, got := "mux.Mount", routes[len(routes)-1].Handler; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Mount_fail(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Mount("http://localhost/api", &mux.Mux{}); err != mux.ErrMountPatternMustBeValid {
+		t.Fatal("expected: mux.ErrMountPatternMustBeValid")
+	}
+	if err := m.Mount("http://localhost/api/{*}", nil); err != mux.ErrMuxMustBeNotNil {
+		t.Fatal("expected: mux.ErrMuxMustBeNotNil")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/api/{*}", newTestHandler("api")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Mount("http://localhost/api/{*}", &mux.Mux{}); err == nil {
+		t.Fatal("expected: a conflict error")
+	}
+	if want, got := 1, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_HandlePrefix_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.HandlePrefix(http.MethodGet, "http://{tenant}.example.com/static/{*}", http.FileServer(http.Dir("."))); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://acme.example.com/static/mount_test.go", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "func TestMux_HandlePrefix_success") {
+		t.Fatalf("want the file, got=%d %q", rr.Code, rr.Body.String())
+	}
+	rr = httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://acme.example.com/static/missing.go", nil))
+	if want, got := http.StatusNotFound, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+	if want, got := "*http.fileHandler", m.Routes()[0].Handler; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_HandlePrefix_fail(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.HandlePrefix(http.MethodGet, "http://localhost/static", http.HandlerFunc(emptyHandler)); err != mux.ErrMountPatternMustBeValid {
+		t.Fatal("expected: mux.ErrMountPatternMustBeValid")
+	}
+	if err := m.HandlePrefix(http.MethodGet, "http://localhost/static/{*}", nil); err != mux.ErrHandlerMustBeNotNil {
+		t.Fatal("expected: mux.ErrHandlerMustBeNotNil")
+	}
+}
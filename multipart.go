@@ -0,0 +1,81 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+)
+
+//Errors returned by the MultipartForm option.
+var (
+	//ErrMultipartFormMustBeValid is returned by Handle method when the MultipartForm option receives a negative size, or a maxMemory greater than maxSize.
+	ErrMultipartFormMustBeValid = errors.New("mux: invalid multipart form limits")
+)
+
+//MultipartForm makes the route require a multipart/form-data body, parsed by the Mux (See `http.Request.ParseMultipartForm`) before the handler is called,
+//so handlers can use `r.MultipartForm` and `r.FormValue` directly.
+//
+//maxMemory is the number of bytes of file parts kept in memory, the rest is stored in temporary files, removed after the handler returns.
+//maxSize limits the whole body. If zero, the body is not limited.
+//
+//Requests are answered with http.StatusUnsupportedMediaType when the body is not multipart/form-data, with http.StatusRequestEntityTooLarge when the body exceeds maxSize
+//and with http.StatusBadRequest when it is malformed. The form is parsed after MaxConcurrent admits the request, and MirrorTo mirrors see an empty body.
+//
+//Errors
+//
+//• mux.ErrMultipartFormMustBeValid
+func MultipartForm(maxMemory, maxSize int64) RouteOption {
+	return func(o *routeOptions) error {
+		if maxMemory < 0 || maxSize < 0 || (maxSize > 0 && maxMemory > maxSize) {
+			return ErrMultipartFormMustBeValid
+		}
+		o.multipart = &multipartLimits{maxMemory: maxMemory, maxSize: maxSize}
+		return nil
+	}
+}
+
+//multipartLimits holds the MultipartForm option parameters.
+type multipartLimits struct {
+	maxMemory int64
+	maxSize   int64
+}
+
+//wrap creates a handler that parses the multipart form before calling the next one.
+func (ml *multipartLimits) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "multipart/form-data" {
+			renderError(w, r, http.StatusUnsupportedMediaType, "The request body must be multipart/form-data.")
+			return
+		}
+		if ml.maxSize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, ml.maxSize)
+		}
+		if err := r.ParseMultipartForm(ml.maxMemory); err != nil {
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				renderError(w, r, http.StatusRequestEntityTooLarge, "The multipart form is too large.")
+				return
+			}
+			renderError(w, r, http.StatusBadRequest, "The multipart form is malformed.")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,75 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_MultipartForm_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.FormValue("name"), " ", len(r.MultipartForm.File["file"]))
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/upload", handler, mux.MultipartForm(1024, 2048)); err != nil {
+		t.Fatal(err)
+	}
+	newForm := func(size int) (string, *bytes.Buffer) {
+		b := &bytes.Buffer{}
+		mw := multipart.NewWriter(b)
+		mw.WriteField("name", "report")
+		fw, _ := mw.CreateFormFile("file", "report.txt")
+		fw.Write(bytes.Repeat([]byte("x"), size))
+		mw.Close()
+		return mw.FormDataContentType(), b
+	}
+
+	small, smallBody := newForm(10)
+	large, largeBody := newForm(4096)
+	tests := []struct {
+		contentType string
+		body        *bytes.Buffer
+		want        string
+	}{
+		{small, smallBody, "200 report 1"},
+		{large, largeBody, "413 Request Entity Too Large\n"},
+		{"multipart/form-data; boundary=x", bytes.NewBufferString("garbage"), "400 Bad Request\n"},
+		{"application/json", bytes.NewBufferString("{}"), "415 Unsupported Media Type\n"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/upload", test.body)
+		req.Header.Set("Content-Type", test.contentType)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Body.String()); test.want != got {
+			t.Fatalf("%s: want=%q, got=%q", strings.SplitN(test.contentType, ";", 2)[0], test.want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failMultipartFormMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/", http.HandlerFunc(emptyHandler), mux.MultipartForm(4096, 1024)); err != mux.ErrMultipartFormMustBeValid {
+		t.Fatal("expected: mux.ErrMultipartFormMustBeValid")
+	}
+}
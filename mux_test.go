@@ -15,6 +15,7 @@
 package mux_test
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -313,21 +314,21 @@ func TestMux_Handle_success(t *testing.T) {
 		}
 	}
 
-	if want, got := `GET+http://localhost/fixed/{variable-path}
-GET+http://localhost/fixed-path/{variable-path}
-GET+http://localhost/root-path
-GET+http://localhost/root-path/{*}
-GET+http://localhost:8080/fixed-path/{variable-path}
-GET+http://localhost:8080/fixed-path/{variable-path}/{variable-subpath}
-GET+https://localhost:8080/a-query-only-path/{variable-path}?query=a
-GET+https://localhost:8080/fixed-path/{variable-path}
-POST+https://localhost:8080/fixed-path/{variable-path}?query=a&query=c
-POST+https://localhost:8080/fixed-path/{variable-path}?presence
-POST+https://localhost:8080/fixed-path/{variable-path}?query=a
-POST+https://localhost:8080/fixed-path/{variable-path}?query=c
-POST+https://localhost:8080/fixed-path/{variable-path}
-GET+https://localhost:8080/fixed-path/{variable-path}/fixed-subpath
-POST+https://localhost:8080/fixed-path/{variable-path}/fixed-subpath
+	if want, got := `GET+http://localhost/fixed/{variable-path} -> gitlab.com/gopherburrow/mux_test.TestMux_Handle_success.func1
+GET+http://localhost/fixed-path/{variable-path} -> *mux_test.testHandler
+GET+http://localhost/root-path -> *mux_test.testHandler
+GET+http://localhost/root-path/{*} -> *mux_test.testHandler
+GET+http://localhost:8080/fixed-path/{variable-path} -> *mux_test.testHandler
+GET+http://localhost:8080/fixed-path/{variable-path}/{variable-subpath} -> *mux_test.testHandler
+GET+https://localhost:8080/a-query-only-path/{variable-path}?query=a -> *mux_test.testHandler
+GET+https://localhost:8080/fixed-path/{variable-path} -> *mux_test.testHandler
+POST+https://localhost:8080/fixed-path/{variable-path}?query=a&query=c -> *mux_test.testHandler
+POST+https://localhost:8080/fixed-path/{variable-path}?presence -> *mux_test.testHandler
+POST+https://localhost:8080/fixed-path/{variable-path}?query=a -> *mux_test.testHandler
+POST+https://localhost:8080/fixed-path/{variable-path}?query=c -> *mux_test.testHandler
+POST+https://localhost:8080/fixed-path/{variable-path} -> *mux_test.testHandler
+GET+https://localhost:8080/fixed-path/{variable-path}/fixed-subpath -> *mux_test.testHandler
+POST+https://localhost:8080/fixed-path/{variable-path}/fixed-subpath -> *mux_test.testHandler
 `, m.String(); want != got {
 		t.Fatalf("want=%q, got=%q", want, got)
 	}
@@ -343,6 +344,31 @@ func TestMux_Handle_successInRootPathAndRootWildcard(t *testing.T) {
 	}
 }
 
+func TestMux_Handle_successEmptyPath(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost:8080", newTestHandler("root")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost:8080/{*}", newTestHandler("sub path")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost:8080/", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if want, got := "[GET+http://localhost:8080/ GET+http://localhost:8080/{*}]", fmt.Sprint(m.Routes()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	for url, want := range map[string]string{"http://localhost:8080": "root", "http://localhost:8080/": "root", "http://localhost:8080/a": "sub path"} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", url, want, got)
+		}
+	}
+}
+
 func TestMux_Handle_successInPathAndPathWildcard(t *testing.T) {
 	m := &mux.Mux{}
 	if err := m.Handle(http.MethodGet, "http://localhost:8080/test", http.HandlerFunc(emptyHandler)); err != nil {
@@ -459,6 +485,15 @@ func TestMux_Handle_failUrlPatternHostMustHaveHostName(t *testing.T) {
 	}
 }
 
+func TestMux_Handle_failUrlPatternHostMustNotBeWildcard(t *testing.T) {
+	m := &mux.Mux{}
+	for _, urlPattern := range []string{"http://*/", "http://*.com/"} {
+		if err := m.Handle(http.MethodGet, urlPattern, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternMustBeValid {
+			t.Fatalf("urlPattern=%q, expected: mux.ErrURLPatternMustBeValid", urlPattern)
+		}
+	}
+}
+
 func TestMux_Handle_failHandlerMustBeNotNil(t *testing.T) {
 	m := &mux.Mux{}
 	err := m.Handle(http.MethodGet, "http://localhost:8080/fixed-path/{variable-path}", nil)
@@ -541,6 +576,39 @@ func TestMux_Handle_failMustNotConflictingWithExistingEntryFixedVsParent(t *test
 	}
 }
 
+func TestMux_Handle_successSameParentDifferentMethods(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost:8080/files/{*}", newTestHandler("GET")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPut, "http://localhost:8080/files/{*}", newTestHandler("PUT")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost:8080/files/{*}?meta", newTestHandler("GET?meta")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost:8080/files/{*}", http.HandlerFunc(emptyHandler)); err != mux.ErrRouteMustNotConflict {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+
+	for _, test := range []struct{ method, url, want string }{
+		{http.MethodGet, "http://localhost:8080/files/a/b", "200 GET"},
+		{http.MethodPut, "http://localhost:8080/files/a/b", "200 PUT"},
+		{http.MethodGet, "http://localhost:8080/files/a/b?meta", "200 GET?meta"},
+		{http.MethodDelete, "http://localhost:8080/files/a/b", "405 "},
+	} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(test.method, test.url, nil))
+		got := fmt.Sprint(rr.Code, " ")
+		if rr.Code == http.StatusOK {
+			got += rr.Body.String()
+		}
+		if test.want != got {
+			t.Fatalf("method=%s, url=%q, want=%q, got=%q", test.method, test.url, test.want, got)
+		}
+	}
+}
+
 func TestMux_Handle_failMustNotConflictingWithExistingEntryFixedTrailingSlashes(t *testing.T) {
 	m := &mux.Mux{}
 	err := m.Handle(http.MethodGet, "http://localhost:8080/fixed-path/", http.HandlerFunc(emptyHandler))
@@ -821,7 +889,7 @@ func TestMux_PathValues_success(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, "https://localhost:8080/parent-path/gopher/burrow", nil)
 		values := m.PathValues(r)
 		if wantSize, wantValue, gotSize, gotValue := 1, "gopher/burrow", len(values), values[0]; wantSize != gotSize || wantValue != gotValue {
-			t.Fatalf("wantSize=%q, wantValue=%q, gotSize=%q, gotValue=%q", wantSize, wantValue, gotSize, gotValue)
+			t.Fatalf("wantSize=%d, wantValue=%q, gotSize=%d, gotValue=%q", wantSize, wantValue, gotSize, gotValue)
 		}
 	}
 
@@ -878,6 +946,35 @@ func TestMux_failMethodNotAllowed(t *testing.T) {
 	if want, got := http.StatusMethodNotAllowed, rr.Code; want != got {
 		t.Fatalf("want=%d, got=%d", want, got)
 	}
+	if want, got := "GET", rr.Header().Get("Allow"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if err := m.Handle(http.MethodPut, "http://localhost/{path}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMux_HideMethods_success(t *testing.T) {
+	m := &mux.Mux{}
+	public := m.Group(mux.HideMethods())
+	if err := public.Handle(http.MethodGet, "http://www.localhost/about", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://api.localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://api.localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+
+	for url, want := range map[string]string{"http://www.localhost/about": "404 ", "http://api.localhost/orders": "405 GET, POST"} {
+		req := httptest.NewRequest(http.MethodDelete, url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Allow")); want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", url, want, got)
+		}
+	}
 }
 
 func ExampleMux() {
@@ -905,3 +1002,354 @@ func ExampleMux() {
 
 	// Output: Hello World "gopher" "burrow/mux"
 }
+
+func TestMux_QueryPolicy_success(t *testing.T) {
+	tests := []struct {
+		policy mux.QueryPolicy
+		query  string
+		want   string
+	}{
+		{mux.QueryAnyValue, "?format=xml&format=pdf", "pdf"},
+		{mux.QueryFirstValue, "?format=csv&format=pdf", "csv"},
+		{mux.QueryFirstValue, "?format=xml&format=pdf", "none"},
+		{mux.QueryLastValue, "?format=csv&format=pdf", "pdf"},
+		{mux.QueryAllValues, "?format=pdf&format=pdf", "pdf"},
+		{mux.QueryAllValues, "?format=csv&format=pdf", "none"},
+		{mux.QueryAllValues, "?format=csv&format=xml", "none"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{QueryPolicy: test.policy}
+		if err := m.Handle(http.MethodGet, "http://localhost/report?format=pdf", newTestHandler("pdf")); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Handle(http.MethodGet, "http://localhost/report?format=csv", newTestHandler("csv")); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Handle(http.MethodGet, "http://localhost/report", newTestHandler("none")); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/report"+test.query, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := test.want, rr.Body.String(); want != got {
+			t.Fatalf("policy=%d, query=%q, want=%q, got=%q", test.policy, test.query, want, got)
+		}
+	}
+}
+
+func TestMux_Handle_successQueryValueConstraints(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={:1-100}", newTestHandler("page")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={:101-200}", newTestHandler("far page")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?sort={:/^-?[a-z]%2B$/}", newTestHandler("sort")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items", newTestHandler("items")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"?page=1", "page"},
+		{"?page=100", "page"},
+		{"?page=101", "far page"},
+		{"?page=0", "items"},
+		{"?page=abc", "items"},
+		{"?sort=-name", "sort"},
+		{"?sort=Name", "items"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/items"+test.query, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := test.want, rr.Body.String(); want != got {
+			t.Fatalf("query=%q, want=%q, got=%q", test.query, want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failQueryValueConstraints(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={:1-100}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page=50", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={:100-110}", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	for _, p := range []string{"http://localhost/items?page={:100-1}", "http://localhost/items?page={:a-b}", "http://localhost/items?page={:/[/}"} {
+		if err := m.Handle(http.MethodGet, p, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternInvalidQueryRoute {
+			t.Fatalf("pattern=%q, expected: mux.ErrURLPatternInvalidQueryRoute", p)
+		}
+	}
+}
+
+func TestMux_Handle_failQueryValueConstraintsNotNeighbours(t *testing.T) {
+	m := &mux.Mux{}
+	//Sorted by their text, ?id=abc is inserted before ?id=zzz, away from the regular expression accepting it.
+	for _, p := range []string{"http://localhost/items?id=zzz", "http://localhost/items?id={:/^a/}"} {
+		if err := m.Handle(http.MethodGet, p, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?id=abc", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?id=bcd", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_Handle_successQueryValueSets(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/report?format={:json|xml}", newTestHandler("structured")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/report?format={:csv|tsv}", newTestHandler("tabular")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/report", newTestHandler("html")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"?format=json", "structured"},
+		{"?format=xml", "structured"},
+		{"?format=tsv", "tabular"},
+		{"?format=pdf", "html"},
+		{"", "html"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/report"+test.query, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := test.want, rr.Body.String(); want != got {
+			t.Fatalf("query=%q, want=%q, got=%q", test.query, want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failQueryValueSets(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/report?format={:json|xml}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"http://localhost/report?format=xml", "http://localhost/report?format={:xml|yaml}", "http://localhost/report?format={:/^x/}"} {
+		if err := m.Handle(http.MethodGet, p, http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+			t.Fatalf("pattern=%q, expected: mux.ErrRouteMustNotConflict", p)
+		}
+	}
+	for _, p := range []string{"http://localhost/report?format={:json|}", "http://localhost/report?format={:|}"} {
+		if err := m.Handle(http.MethodGet, p, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternInvalidQueryRoute {
+			t.Fatalf("pattern=%q, expected: mux.ErrURLPatternInvalidQueryRoute", p)
+		}
+	}
+}
+
+func TestMux_Handle_successQueryAbsenceTests(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?!debug", newTestHandler("plain")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?debug", newTestHandler("debug")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?!debug&page={:1-100}", newTestHandler("page")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "plain"},
+		{"?debug", "debug"},
+		{"?debug=1&page=2", "debug"},
+		{"?page=2", "page"},
+		{"?page=200", "plain"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/items"+test.query, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := test.want, rr.Body.String(); want != got {
+			t.Fatalf("query=%q, want=%q, got=%q", test.query, want, got)
+		}
+	}
+	if want, got := "GET+http://localhost/items?!debug&page={:1-100}", m.Routes()[0].String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/items?!debug"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMux_Handle_failQueryAbsenceTests(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?!debug", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?!debug", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	for _, p := range []string{"http://localhost/items?!", "http://localhost/items?!debug=1", "http://localhost/items?!debug&debug", "http://localhost/items?!debug&!debug"} {
+		if err := m.Handle(http.MethodGet, p, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternInvalidQueryRoute {
+			t.Fatalf("pattern=%q, expected: mux.ErrURLPatternInvalidQueryRoute", p)
+		}
+	}
+}
+
+func TestMux_SemicolonPolicy_success(t *testing.T) {
+	tests := []struct {
+		policy     mux.SemicolonPolicy
+		wantStatus int
+		wantBody   string
+	}{
+		{mux.SemicolonIgnore, http.StatusOK, "plain"},
+		{mux.SemicolonReject, http.StatusBadRequest, "Bad Request\n"},
+		{mux.SemicolonAccept, http.StatusOK, "format"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{SemicolonPolicy: test.policy}
+		if err := m.Handle(http.MethodGet, "http://localhost/report?format=pdf&page", newTestHandler("format")); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Handle(http.MethodGet, "http://localhost/report", newTestHandler("plain")); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/report?format=pdf;page=1", nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := test.wantStatus, rr.Code; want != got {
+			t.Fatalf("policy=%d, want=%d, got=%d", test.policy, want, got)
+		}
+		if want, got := test.wantBody, rr.Body.String(); want != got {
+			t.Fatalf("policy=%d, want=%q, got=%q", test.policy, want, got)
+		}
+	}
+}
+
+func TestMux_AssumeScheme_success(t *testing.T) {
+	m := &mux.Mux{AssumeScheme: "https"}
+	if err := m.Handle(http.MethodGet, "https://localhost/{path}", newTestHandler("https")); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/gopher", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "https", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "gopher", m.PathVars(req)["path"]; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_AnyScheme_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "//localhost/{path}", newTestHandler("any")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "https://localhost/secure", newTestHandler("https")); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "GET+//localhost/{path}", m.Routes()[0].String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	for url, want := range map[string]string{
+		"http://localhost/gopher":  "any",
+		"https://localhost/gopher": "any",
+		"https://localhost/secure": "https",
+		"http://localhost/secure":  "any",
+	} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("%s: want=%q, got=%q", url, want, got)
+		}
+	}
+
+	if err := m.RemoveHandler(http.MethodGet, "//localhost/{path}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "localhost/path", newTestHandler("any")); err != mux.ErrURLPatternMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrURLPatternMustBeValid, err)
+	}
+}
+
+func TestMux_QueryVars_success(t *testing.T) {
+	m := &mux.Mux{}
+	var vars map[string]string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars = m.QueryVars(r)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={page}&sort={sort}&view=list", h); err != nil {
+		t.Fatal(err)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/items?sort=name&page=2&page=3&view=list", nil))
+	if len(vars) != 2 || vars["page"] != "2" || vars["sort"] != "name" {
+		t.Fatalf("unexpected vars: %v", vars)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost/items?page=2&view=list", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected: %d, got: %d", http.StatusNotFound, w.Code)
+	}
+	if routes := m.Routes(); routes[0].URLPattern != "http://localhost/items?page={page}&sort={sort}&view=list" {
+		t.Fatalf("unexpected pattern: %s", routes[0].URLPattern)
+	}
+}
+
+func TestMux_QueryVars_lastValue(t *testing.T) {
+	m := &mux.Mux{QueryPolicy: mux.QueryLastValue}
+	var vars map[string]string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars = m.QueryVars(r)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={page}", h); err != nil {
+		t.Fatal(err)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/items?page=2&page=3", nil))
+	if vars["page"] != "3" {
+		t.Fatalf("expected: 3, got: %v", vars)
+	}
+	if vars := m.QueryVars(httptest.NewRequest(http.MethodGet, "http://localhost/items?page=2", nil)); len(vars) != 0 {
+		t.Fatalf("expected: no vars outside the Mux, got: %v", vars)
+	}
+}
+
+func TestMux_QueryVars_conflictsWithPresenceTest(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?page={page}", http.HandlerFunc(emptyHandler)); err == nil {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+}
+
+func TestMux_QueryVars_failInvalidQueryRoute(t *testing.T) {
+	m := &mux.Mux{}
+	for _, pattern := range []string{"http://localhost/items?page={}", "http://localhost/items?page={a:b}", "http://localhost/items?page={page}&page=1"} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternInvalidQueryRoute {
+			t.Fatalf("%s expected: mux.ErrURLPatternInvalidQueryRoute, got: %v", pattern, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//PathNormalization defines how request paths are normalized before matching, so handlers do not have to defend against path tricks. The values can be combined. Eg: NormalizeSlashes | NormalizeDots.
+type PathNormalization int
+
+//Path normalizations used in Mux.PathNormalization field.
+const (
+	//NormalizeSlashes collapses repeated slashes. Eg: /api//users is matched as /api/users.
+	NormalizeSlashes PathNormalization = 1 << iota
+	//NormalizeDots resolves the dot-segments "." and "..", also when percent-encoded (Eg: %2e%2e). Eg: /api/./v1/../users is matched as /api/users.
+	//Requests whose dot-segments climb above the root path (Eg: /../etc/passwd) are answered with http.StatusBadRequest.
+	NormalizeDots
+	//NormalizePath applies all normalizations.
+	NormalizePath = NormalizeSlashes | NormalizeDots
+)
+
+//normalizePath applies the PathNormalization of the Mux to the request path, returning a request with the normalized URL.
+//If the path climbs above the root path, ok is false.
+//
+//Encoded slashes treated as separators by the EncodedSlashPolicy of the Mux are separators here too, so they cannot hide dot-segments (Eg: /a/..%2f..%2fetc).
+func (m *Mux) normalizePath(r *http.Request) (nr *http.Request, ok bool) {
+	path := separateEncodedSlashes(r.URL.EscapedPath(), m.EncodedSlashPolicy)
+	if !strings.HasPrefix(path, "/") {
+		return r, true
+	}
+	segs := strings.Split(path[1:], "/")
+	slashes, dots := m.PathNormalization&NormalizeSlashes != 0, m.PathNormalization&NormalizeDots != 0
+
+	//A trailing slash (or a trailing dot-segment, when resolved) is kept after the normalization.
+	trailing := false
+	switch last := segs[len(segs)-1]; {
+	case last == "":
+		trailing = len(segs) > 1
+		segs = segs[:len(segs)-1]
+	case dots && (isDotSegment(last) || isDotDotSegment(last)):
+		trailing = true
+	}
+	out := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		switch {
+		case seg == "" && slashes:
+		case isDotSegment(seg) && dots:
+		case isDotDotSegment(seg) && dots:
+			if len(out) == 0 {
+				return r, false
+			}
+			out = out[:len(out)-1]
+		default:
+			out = append(out, seg)
+		}
+	}
+	normalized := "/" + strings.Join(out, "/")
+	if trailing && len(out) > 0 {
+		normalized += "/"
+	}
+	if normalized == path {
+		return r, true
+	}
+	unescaped, err := url.PathUnescape(normalized)
+	if err != nil {
+		return r, true
+	}
+	u := *r.URL
+	u.Path, u.RawPath = unescaped, normalized
+	r = r.WithContext(r.Context())
+	r.URL = &u
+	return r, true
+}
+
+//isDotSegment tests if a path segment is ".", percent-encoded or not.
+func isDotSegment(seg string) bool {
+	return seg == "." || strings.EqualFold(seg, "%2e")
+}
+
+//isDotDotSegment tests if a path segment is "..", percent-encoded or not.
+func isDotDotSegment(seg string) bool {
+	switch strings.ToLower(seg) {
+	case "..", ".%2e", "%2e.", "%2e%2e":
+		return true
+	}
+	return false
+}
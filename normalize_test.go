@@ -0,0 +1,93 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_PathNormalization_success(t *testing.T) {
+	tests := []struct {
+		normalization mux.PathNormalization
+		path, want    string
+	}{
+		{0, "/api//users", "404 "},
+		{0, "/api/./users", "404 "},
+		{mux.NormalizeSlashes, "/api//users", "200 /api/users"},
+		{mux.NormalizeSlashes, "/api/./users", "404 "},
+		{mux.NormalizeDots, "/api/./v1/../users", "200 /api/users"},
+		{mux.NormalizeDots, "/api/%2e/v1/%2E%2e/users", "200 /api/users"},
+		{mux.NormalizeDots, "/api//users", "404 "},
+		{mux.NormalizeDots, "/api/users/..", "200 /api/"},
+		{mux.NormalizeDots, "/../etc/passwd", "400 "},
+		{mux.NormalizeDots, "/api/%2e%2e/%2e%2e/etc/passwd", "400 "},
+		{mux.NormalizePath, "//api/.//users/", "200 /api/users/"},
+		{mux.NormalizePath, "/files/a%2Fb/./c", "200 /files/a%2Fb/c"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{PathNormalization: test.normalization}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, r.URL.EscapedPath())
+		})
+		for _, pattern := range []string{"http://localhost/api/users", "http://localhost/api/", "http://localhost/files/{*}"} {
+			if err := m.Handle(http.MethodGet, pattern, handler); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil))
+		got := fmt.Sprint(rr.Code, " ")
+		if rr.Code == http.StatusOK {
+			got += rr.Body.String()
+		}
+		if test.want != got {
+			t.Fatalf("normalization=%d, path=%q, want=%q, got=%q", test.normalization, test.path, test.want, got)
+		}
+	}
+}
+
+func TestMux_PathNormalization_successEncodedSlashes(t *testing.T) {
+	tests := []struct {
+		policy     mux.EncodedSlashPolicy
+		path, want string
+	}{
+		{mux.EncodedSlashSeparate, "/a/..%2f..%2fetc", "400 "},
+		{mux.EncodedSlashSeparate, "/a/..%2F..%2F..%2Fetc/passwd", "400 "},
+		{mux.EncodedSlashSeparate, "/a/b/..%2fc", "200 map[*:c]"},
+		{mux.EncodedSlashKeep, "/a/..%2f..%2fetc", "200 map[*:..%2f..%2fetc]"},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{PathNormalization: mux.NormalizePath, EncodedSlashPolicy: test.policy}
+		if err := m.Handle(http.MethodGet, "http://localhost/a/{*}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, m.PathVars(r))
+		})); err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil))
+		got := fmt.Sprint(rr.Code, " ")
+		if rr.Code == http.StatusOK {
+			got += rr.Body.String()
+		}
+		if test.want != got {
+			t.Fatalf("policy=%v, path=%q, want=%q, got=%q", test.policy, test.path, test.want, got)
+		}
+	}
+}
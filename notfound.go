@@ -0,0 +1,169 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+//Errors returned by the NotFound option.
+var (
+	//ErrNotFoundHandlerMustBeNotNil is returned by Handle method when the NotFound option receives a nil handler.
+	ErrNotFoundHandlerMustBeNotNil = errors.New("mux: not found handler must be not nil")
+)
+
+const (
+	//Used in request contexts.
+	ctxNotFoundValue = "gitlab.com/gopherburrow/mux NotFound"
+)
+
+//The key used to store the not found diagnostics in NotFoundHandler calls.
+var ctxNotFound = ctxType(ctxNotFoundValue)
+
+//NotFoundDiagnostics explains why a request did not match any route, so custom 404 pages and logs can explain near misses.
+type NotFoundDiagnostics struct {
+	//PathMatched tells if some route matched the request scheme, host and path.
+	PathMatched bool
+	//MethodMatched tells if some route matched the request path and method, so only the query routing or the matchers did not match.
+	MethodMatched bool
+	//Candidates are the nearest routes in routing table order: the routes that matched the path (and the method, when MethodMatched is true),
+	//or else the routes of the same scheme and host sorted immediately before and after the request path.
+	Candidates []RouteInfo
+	//CandidateVars holds the variables of each candidate extracted from the request path, when Mux.NotFoundVars is set. Eg: {"id": "42"} for /users/42/profil and the candidate /users/{id}/profile .
+	//Only the segments before the first static segment that differs are extracted, so smart 404 pages can suggest links ("did you mean /users/42/profile?").
+	CandidateVars []map[string]string
+}
+
+//NotFoundInfo retrieves the diagnostics of a request passed by a Mux to its NotFoundHandler.
+//
+//Possible error returns:
+//
+//• mux.ErrRequestMustHaveContext
+func NotFoundInfo(r *http.Request) (NotFoundDiagnostics, error) {
+	info, ok := r.Context().Value(ctxNotFound).(NotFoundDiagnostics)
+	if !ok {
+		return NotFoundDiagnostics{}, ErrRequestMustHaveContext
+	}
+	return info, nil
+}
+
+//NotFound sets the handler of the requests not found in the scope of the route, overriding Mux.NotFoundHandler. Eg: an /api group answering JSON 404s while the website answers HTML.
+//
+//It is mostly useful in groups. The scope of a route is its scheme, host and path without the last segment (Eg: http://localhost/api for http://localhost/api/users),
+//and the handler of the most specific scope covering the request path is used, cascading to Mux.NotFoundHandler. So, nested groups override the handler of their outer groups.
+//The handler receives the same diagnostics as Mux.NotFoundHandler (See NotFoundInfo function).
+//
+//Errors
+//
+//• mux.ErrNotFoundHandlerMustBeNotNil
+func NotFound(h http.Handler) RouteOption {
+	return func(o *routeOptions) error {
+		if h == nil {
+			return ErrNotFoundHandlerMustBeNotNil
+		}
+		o.notFound = h
+		return nil
+	}
+}
+
+//notFoundHandler finds the NotFound option handler of the most specific route scope covering the request path, or Mux.NotFoundHandler.
+func (m *Mux) notFoundHandler(r *http.Request) http.Handler {
+	scheme, reqSegs := m.requestScheme(r), m.requestPathSegs(r)
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	host := m.routingHost(r.Host)
+	h, depth := m.NotFoundHandler, -1
+	for _, e := range m.entries {
+		if e.options.notFound == nil || compareSchemeHost(scheme, e.route.scheme, host, e.route.host) != 0 {
+			continue
+		}
+		scope := e.route.path
+		if len(scope) > 0 {
+			scope = scope[:len(scope)-1]
+		}
+		if len(scope) > depth && coversPath(scope, reqSegs) {
+			h, depth = e.options.notFound, len(scope)
+		}
+	}
+	return h
+}
+
+//coversPath tests if the scope segments are a prefix of the request path segments. Variables cover any segment.
+func coversPath(scope, reqSegs []string) bool {
+	if len(scope) > len(reqSegs) {
+		return false
+	}
+	for i, seg := range scope {
+		if _, _, isVar := parsePathVar(seg); !isVar && seg != reqSegs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//neighbours describes the entries of the same scheme and host sorted immediately before and after the position i.
+func (entries muxEntries) neighbours(i int, scheme, host string) []RouteInfo {
+	routes := []RouteInfo{}
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(entries) {
+			continue
+		}
+		if compareSchemeHost(scheme, entries[j].route.scheme, host, entries[j].route.host) != 0 {
+			continue
+		}
+		routes = append(routes, newRouteInfo(entries[j]))
+	}
+	return routes
+}
+
+//candidateVars extracts the variables of each candidate route from the request path segments.
+func (m *Mux) candidateVars(r *http.Request, candidates []RouteInfo) []map[string]string {
+	reqSegs := m.splitRequestPath(r)
+	vars := make([]map[string]string, len(candidates))
+	for i, ri := range candidates {
+		vars[i] = m.partialVars(ri, reqSegs)
+	}
+	return vars
+}
+
+//partialVars extracts the variables of a route from the request path segments, up to the first static segment that differs.
+func (m *Mux) partialVars(ri RouteInfo, reqSegs []string) map[string]string {
+	vars := map[string]string{}
+	route, err := newMuxRoute(ri.Method, ri.URLPattern)
+	if err != nil {
+		return vars
+	}
+	for i, seg := range route.path {
+		if i >= len(reqSegs) {
+			break
+		}
+		name, _, isVar := parsePathVar(seg)
+		if !isVar {
+			if seg != reqSegs[i] && !(m.PathCollation == PathCaseInsensitive && strings.EqualFold(seg, reqSegs[i])) {
+				break
+			}
+			continue
+		}
+		if name == "*" {
+			vars[name] = strings.Join(reqSegs[i:], "/")
+			break
+		}
+		vars[name] = reqSegs[i]
+	}
+	return vars
+}
@@ -0,0 +1,137 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestNotFoundInfo_success(t *testing.T) {
+	m := &mux.Mux{}
+	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := mux.NotFoundInfo(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(w, info.PathMatched, " ", info.MethodMatched, " ", info.Candidates)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/orders?api-key", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/users", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://example.com/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"http://localhost/orders":   "true true [GET+http://localhost/orders?api-key]",
+		"http://localhost/products": "false false [GET+http://localhost/orders?api-key GET+http://localhost/users]",
+		"http://unknown.com/orders": "false false []",
+	}
+	for url, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", url, want, got)
+		}
+	}
+}
+
+func TestNotFoundInfo_successCandidateVars(t *testing.T) {
+	m := &mux.Mux{NotFoundVars: true}
+	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := mux.NotFoundInfo(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(w, info.Candidates, " ", info.CandidateVars)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/users/{id}/profile", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/users/{id}/orders/{order}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"http://localhost/users/42/profil":       "[GET+http://localhost/users/{id}/orders/{order} GET+http://localhost/users/{id}/profile] [map[id:42] map[id:42]]",
+		"http://localhost/users/42/orders/7/x":   "[GET+http://localhost/users/{id}/orders/{order} GET+http://localhost/users/{id}/profile] [map[id:42 order:7] map[id:42]]",
+		"http://localhost/users/42/orders/7?a=b": "",
+	}
+	for url, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := rr.Body.String(); want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", url, want, got)
+		}
+	}
+}
+
+func TestNotFoundInfo_failMustHaveContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	if _, err := mux.NotFoundInfo(req); err != mux.ErrRequestMustHaveContext {
+		t.Fatal("expected: mux.ErrRequestMustHaveContext")
+	}
+}
+
+func TestMux_NotFound_successCascade(t *testing.T) {
+	m := &mux.Mux{NotFoundHandler: newTestHandler("default")}
+	site := m.Group(mux.NotFound(newTestHandler("html")))
+	api := m.Group(mux.NotFound(newTestHandler("json")))
+	v2 := api.Group(mux.NotFound(newTestHandler("json v2")))
+	if err := site.Handle(http.MethodGet, "http://localhost/about", newTestHandler("about")); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.Handle(http.MethodGet, "http://localhost/api/users/{id}", newTestHandler("user")); err != nil {
+		t.Fatal(err)
+	}
+	if err := v2.Handle(http.MethodGet, "http://localhost/api/v2/users", newTestHandler("users v2")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url, want string
+	}{
+		{"http://localhost/contact", "html"},
+		{"http://localhost/api/orders", "html"},
+		{"http://localhost/api/users/42/orders", "json"},
+		{"http://localhost/api/v2/orders", "json v2"},
+		{"http://localhost/about", "about"},
+		{"http://example.com/about", "default"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+}
+
+func TestMux_Handle_failNotFoundHandlerMustBeNotNil(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.NotFound(nil)); err != mux.ErrNotFoundHandlerMustBeNotNil {
+		t.Fatal("expected: mux.ErrNotFoundHandlerMustBeNotNil")
+	}
+}
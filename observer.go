@@ -0,0 +1,147 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"bufio"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+//Errors returned by the TraceSampleRate option.
+var (
+	//ErrTraceSampleRateMustBeValid is returned by Handle method when the TraceSampleRate option is not between 0 and 1.
+	ErrTraceSampleRateMustBeValid = errors.New("mux: trace sample rate must be between 0 and 1")
+)
+
+//Observer is an instrumentation hook (Eg: for tracing or metrics) set in Mux.Observer field.
+//
+//It is notified around each request dispatched to a route handler, receiving the description of the matched route.
+type Observer interface {
+	//Begin is called before the route handler. The returned request is the one passed to the handler, so a tracing span can be attached to its context.
+	Begin(r *http.Request, route RouteInfo) *http.Request
+	//End is called after the route handler returns, with the request returned by Begin.
+	End(r *http.Request, route RouteInfo, o Observation)
+}
+
+//Observation describes the outcome of a dispatched request.
+type Observation struct {
+	//Status is the HTTP status code written by the handler.
+	Status int
+	//Duration is the time spent in the handler.
+	Duration time.Duration
+	//RequestID is the id assigned to the request, when Mux.RequestIDs is set.
+	RequestID string
+	//BytesWritten is the number of response body bytes written by the handler.
+	BytesWritten int64
+}
+
+//TraceSampleRate sets the fraction (between 0 and 1) of the route requests that should be traced.
+//
+//The rate is not used by the Mux itself. It is consumed by the Observer through RouteInfo, so high-volume routes (Eg: health checks) can be sampled down while critical ones are always traced.
+//Routes without this option have a rate of 1.
+//
+//Errors
+//
+//• mux.ErrTraceSampleRateMustBeValid
+func TraceSampleRate(rate float64) RouteOption {
+	return func(o *routeOptions) error {
+		if rate < 0 || rate > 1 {
+			return ErrTraceSampleRateMustBeValid
+		}
+		o.traceSampleRate = rate
+		o.hasTraceSampleRate = true
+		return nil
+	}
+}
+
+//SampleTrace randomly decides if a request to the route should be traced, according to its TraceSampleRate.
+func (ri RouteInfo) SampleTrace() bool {
+	return ri.TraceSampleRate >= 1 || rand.Float64() < ri.TraceSampleRate
+}
+
+//responseWriter wraps a `http.ResponseWriter` capturing the status code and counting the body bytes written by handlers.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+	//max limits the body bytes, when positive. Bytes over it are discarded (See `mux.MaxResponseSize`).
+	max      int64
+	exceeded bool
+}
+
+//WriteHeader captures the status code.
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	//Informational responses (Eg: 103 Early Hints) are not the final status.
+	if rw.status == 0 && (statusCode < 100 || statusCode > 199) {
+		rw.status = statusCode
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+//Write captures the implicit http.StatusOK status code and counts the written bytes.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	if rw.max > 0 && rw.written+int64(len(b)) > rw.max {
+		n, err := rw.ResponseWriter.Write(b[:rw.max-rw.written])
+		rw.written += int64(n)
+		rw.exceeded = true
+		if err == nil {
+			err = ErrResponseTooLarge
+		}
+		return n, err
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.written += int64(n)
+	return n, err
+}
+
+//Flush implements `http.Flusher` when the wrapped writer does.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+//Hijack implements `http.Hijacker` when the wrapped writer does.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
+//Unwrap returns the wrapped writer, so `http.ResponseController` can reach it.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+//statusCode returns the captured status code. Handlers that write nothing produce an implicit http.StatusOK.
+func (rw *responseWriter) statusCode() int {
+	if rw.status == 0 {
+		return http.StatusOK
+	}
+	return rw.status
+}
@@ -0,0 +1,69 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type testObserver struct {
+	events []string
+}
+
+func (o *testObserver) Begin(r *http.Request, route mux.RouteInfo) *http.Request {
+	o.events = append(o.events, fmt.Sprintf("begin %s %v %t", route, route.TraceSampleRate, route.SampleTrace()))
+	return r
+}
+
+func (o *testObserver) End(r *http.Request, route mux.RouteInfo, obs mux.Observation) {
+	o.events = append(o.events, fmt.Sprintf("end %s %d", route, obs.Status))
+}
+
+func TestMux_Observer_success(t *testing.T) {
+	o := &testObserver{}
+	m := &mux.Mux{Observer: o}
+	if err := m.Handle(http.MethodGet, "http://localhost/health", http.HandlerFunc(emptyHandler), mux.TraceSampleRate(0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/payments", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "http://localhost/health", nil),
+		httptest.NewRequest(http.MethodPost, "http://localhost/payments", nil),
+		httptest.NewRequest(http.MethodGet, "http://localhost/not-found", nil),
+	} {
+		m.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if want, got := "[begin GET+http://localhost/health 0 false end GET+http://localhost/health 200 begin POST+http://localhost/payments 1 true end POST+http://localhost/payments 201]", fmt.Sprint(o.events); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Handle_failTraceSampleRateMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/health", http.HandlerFunc(emptyHandler), mux.TraceSampleRate(1.5)); err != mux.ErrTraceSampleRateMustBeValid {
+		t.Fatal("expected: mux.ErrTraceSampleRateMustBeValid")
+	}
+}
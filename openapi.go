@@ -0,0 +1,240 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+//Annotate documents a route with a short summary and a longer description, used in the generated OpenAPI document.
+func Annotate(summary, description string) RouteOption {
+	return func(o *routeOptions) error {
+		o.summary = summary
+		o.description = description
+		return nil
+	}
+}
+
+//OpenAPI generates an OpenAPI 3 document (JSON) describing the registered routes.
+//
+//As OpenAPI paths are relative, each operation lists the scheme and host of its routes as servers.
+//Routes with the same path and method, but different hosts or query routing, are merged in a single operation.
+//Path variables and query routing tests are documented as required parameters.
+func (m *Mux) OpenAPI() ([]byte, error) {
+	title, version := m.APITitle, m.APIVersion
+	if title == "" {
+		title = "API"
+	}
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	paths := map[string]map[string]*openAPIOperation{}
+	for _, ri := range m.routesWithInternals() {
+		route := ri.route
+		path := openAPIPath(route)
+		if paths[path] == nil {
+			paths[path] = map[string]*openAPIOperation{}
+		}
+		method := strings.ToLower(route.method)
+		op := paths[path][method]
+		if op == nil {
+			op = &openAPIOperation{
+				Summary:     ri.info.Summary,
+				Description: ri.info.Description,
+				Responses:   map[string]openAPIResponse{"default": {Description: "Response"}},
+			}
+			for _, seg := range route.path {
+				if name, kind, ok := parsePathVar(seg); ok {
+					schema := openAPISchema{Type: "string"}
+					if t, typed := pathVarTypes[kind]; typed {
+						schema = t.schema
+					}
+					op.Parameters = append(op.Parameters, openAPIParameter{In: "path", Name: name, Required: true, Schema: schema})
+				}
+			}
+			paths[path][method] = op
+		}
+		op.addServer(strings.TrimPrefix(route.scheme+"://", anyScheme+":") + route.host)
+		op.addRoute(ri.info.String())
+		op.addQuery(route.query)
+	}
+
+	//Route patterns contain "&", so HTML escaping is disabled for readability.
+	b := bytes.Buffer{}
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":   title,
+			"version": version,
+		},
+		"paths": paths,
+	}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+//ServeOpenAPI registers a GET route serving the OpenAPI document generated by OpenAPI method.
+//
+//The document is generated on each request, so it is kept in sync as routes are added or removed at runtime.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+func (m *Mux) ServeOpenAPI(urlPattern string, opts ...RouteOption) error {
+	handler := m.serveJSON(m.OpenAPI)
+	if err := m.Handle(http.MethodGet, urlPattern, handler, opts...); err != nil {
+		return err
+	}
+	m.entriesLock.Lock()
+	m.openAPIURL = urlPattern
+	m.entriesLock.Unlock()
+	return nil
+}
+
+//routeInternals joins a route description with its internal representation.
+type routeInternals struct {
+	info  RouteInfo
+	route *muxRoute
+}
+
+//routesWithInternals is like Routes, but also returns the internal representation of routes.
+func (m *Mux) routesWithInternals() []routeInternals {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	routes := make([]routeInternals, len(m.entries))
+	for i, e := range m.entries {
+		routes[i] = routeInternals{info: newRouteInfo(e), route: e.route}
+	}
+	return routes
+}
+
+//openAPIPath converts a route path to an OpenAPI path template.
+func openAPIPath(route *muxRoute) string {
+	segs := make([]string, len(route.path))
+	for i, seg := range route.path {
+		if name, ok := pathVarName(seg); ok {
+			seg = "{" + name + "}"
+		}
+		segs[i] = seg
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+//pathVarName extracts the variable name of a path segment, without its type.
+func pathVarName(seg string) (string, bool) {
+	name, _, isVar := parsePathVar(seg)
+	return name, isVar
+}
+
+//openAPIOperation is an OpenAPI Operation Object.
+type openAPIOperation struct {
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Servers     []openAPIServer            `json:"servers"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Routes      []string                   `json:"x-mux-routes"`
+}
+
+//addServer adds a server if it is not already listed.
+func (op *openAPIOperation) addServer(url string) {
+	for _, s := range op.Servers {
+		if s.URL == url {
+			return
+		}
+	}
+	op.Servers = append(op.Servers, openAPIServer{URL: url})
+}
+
+//addRoute lists a route merged in the operation.
+func (op *openAPIOperation) addRoute(route string) {
+	op.Routes = append(op.Routes, route)
+}
+
+//addQuery documents the query routing tests of a route, merging value tests of the same parameter.
+func (op *openAPIOperation) addQuery(query queryRoute) {
+	for _, q := range query {
+		if q.absent {
+			continue
+		}
+		i := 0
+		for ; i < len(op.Parameters) && !(op.Parameters[i].In == "query" && op.Parameters[i].Name == q.Name); i++ {
+		}
+		if i == len(op.Parameters) {
+			op.Parameters = append(op.Parameters, openAPIParameter{In: "query", Name: q.Name, Required: true, Schema: openAPISchema{Type: "string"}})
+		}
+		if q.Value == "" {
+			//Captured values are documented as any string.
+			op.Parameters[i].AllowEmptyValue = q.capture == ""
+			continue
+		}
+		if c := q.constraint; c != nil {
+			if c.set != nil {
+				for _, v := range c.set {
+					if !containsString(op.Parameters[i].Schema.Enum, v) {
+						op.Parameters[i].Schema.Enum = append(op.Parameters[i].Schema.Enum, v)
+					}
+				}
+				continue
+			}
+			if c.isRange {
+				min, max := c.min, c.max
+				op.Parameters[i].Schema.Type = "integer"
+				op.Parameters[i].Schema.Minimum, op.Parameters[i].Schema.Maximum = &min, &max
+				continue
+			}
+			op.Parameters[i].Schema.Pattern = c.re.String()
+			continue
+		}
+		if !containsString(op.Parameters[i].Schema.Enum, q.Value) {
+			op.Parameters[i].Schema.Enum = append(op.Parameters[i].Schema.Enum, q.Value)
+		}
+	}
+}
+
+//openAPIServer is an OpenAPI Server Object.
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+//openAPIParameter is an OpenAPI Parameter Object.
+type openAPIParameter struct {
+	Name            string        `json:"name"`
+	In              string        `json:"in"`
+	Required        bool          `json:"required"`
+	AllowEmptyValue bool          `json:"allowEmptyValue,omitempty"`
+	Schema          openAPISchema `json:"schema"`
+}
+
+//openAPISchema is an OpenAPI Schema Object.
+type openAPISchema struct {
+	Type    string   `json:"type"`
+	Format  string   `json:"format,omitempty"`
+	Enum    []string `json:"enum,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+	Minimum *int64   `json:"minimum,omitempty"`
+	Maximum *int64   `json:"maximum,omitempty"`
+}
+
+//openAPIResponse is an OpenAPI Response Object.
+type openAPIResponse struct {
+	Description string `json:"description"`
+}
@@ -0,0 +1,58 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ServeOpenAPI_success(t *testing.T) {
+	m := &mux.Mux{APITitle: "Orders"}
+	if err := m.ServeOpenAPI("https://localhost/openapi.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "https://localhost/orders/{ id }?format=pdf", http.HandlerFunc(emptyHandler), mux.Annotate("Show order", "Shows an order as PDF.")); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func() string {
+		req := httptest.NewRequest(http.MethodGet, "https://localhost/openapi.json", nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := "application/json", rr.Header().Get("Content-Type"); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+		return rr.Body.String()
+	}
+
+	if want, got := `{"info":{"title":"Orders","version":"1.0.0"},"openapi":"3.0.3","paths":{"/openapi.json":{"get":{"servers":[{"url":"https://localhost"}],"responses":{"default":{"description":"Response"}},"x-mux-routes":["GET+https://localhost/openapi.json"]}},"/orders/{id}":{"get":{"summary":"Show order","description":"Shows an order as PDF.","servers":[{"url":"https://localhost"}],"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}},{"name":"format","in":"query","required":true,"schema":{"type":"string","enum":["pdf"]}}],"responses":{"default":{"description":"Response"}},"x-mux-routes":["GET+https://localhost/orders/{ id }?format=pdf"]}}}}`, serve(); want != got {
+		t.Fatalf("want=%s, got=%s", want, got)
+	}
+
+	//Changes in routing table are reflected.
+	if err := m.Handle(http.MethodGet, "http://localhost/orders/{id}?format=csv&verbose", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RemoveHandler(http.MethodGet, "https://localhost/orders/{ id }?format=pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := `{"info":{"title":"Orders","version":"1.0.0"},"openapi":"3.0.3","paths":{"/openapi.json":{"get":{"servers":[{"url":"https://localhost"}],"responses":{"default":{"description":"Response"}},"x-mux-routes":["GET+https://localhost/openapi.json"]}},"/orders/{id}":{"get":{"servers":[{"url":"http://localhost"}],"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}},{"name":"format","in":"query","required":true,"schema":{"type":"string","enum":["csv"]}},{"name":"verbose","in":"query","required":true,"allowEmptyValue":true,"schema":{"type":"string"}}],"responses":{"default":{"description":"Response"}},"x-mux-routes":["GET+http://localhost/orders/{id}?format=csv&verbose"]}}}}`, serve(); want != got {
+		t.Fatalf("want=%s, got=%s", want, got)
+	}
+}
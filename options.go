@@ -0,0 +1,91 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//withOptionsMethod adds OPTIONS to the sorted methods of a path answered by the automatic OPTIONS responder.
+func withOptionsMethod(allow []string) []string {
+	i := sort.SearchStrings(allow, http.MethodOptions)
+	if i < len(allow) && allow[i] == http.MethodOptions {
+		return allow
+	}
+	methods := make([]string, 0, len(allow)+1)
+	methods = append(methods, allow[:i]...)
+	methods = append(methods, http.MethodOptions)
+	return append(methods, allow[i:]...)
+}
+
+//autoOptions answers an OPTIONS request to a path without an explicit OPTIONS route, listing the path methods in the Allow header.
+func (m *Mux) autoOptions(w http.ResponseWriter, allow []string) {
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//OptionsOverrides returns the explicit OPTIONS routes, in routing table order.
+//
+//When Mux.AutoOptions is set, these routes take precedence over the automatic OPTIONS responder for their path:
+//OPTIONS requests to the path not accepted by them (Eg: due to query routing or matchers) are not found, instead of answered automatically.
+//Reviewing them helps to find custom CORS negotiation that diverges from the automatic behavior.
+func (m *Mux) OptionsOverrides() []RouteInfo {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	var overrides []RouteInfo
+	for _, e := range m.entries {
+		if e.route.method == http.MethodOptions {
+			overrides = append(overrides, newRouteInfo(e))
+		}
+	}
+	return overrides
+}
+
+//isAsteriskOptions tests if a request is a server-wide OPTIONS * request (asterisk-form request target, RFC 7230).
+func isAsteriskOptions(r *http.Request) bool {
+	return r.Method == http.MethodOptions && (r.RequestURI == "*" || r.URL.Path == "*")
+}
+
+//asteriskOptions answers an OPTIONS * request by AsteriskOptionsHandler, or automatically listing the methods of the request host routes in the Allow header.
+func (m *Mux) asteriskOptions(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case m.AsteriskOptionsHandler != nil:
+		m.AsteriskOptionsHandler.ServeHTTP(w, r)
+	case m.AutoOptions:
+		m.autoOptions(w, withOptionsMethod(m.hostMethods(m.requestScheme(r), r.Host)))
+	default:
+		m.notFound(w, r, NotFoundDiagnostics{})
+	}
+}
+
+//hostMethods lists the sorted distinct methods of the routes of a request scheme and host, including the routes of any scheme and of wildcard hosts. Routes hiding their methods are skipped.
+func (m *Mux) hostMethods(scheme, host string) []string {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	host = m.routingHost(host)
+	hosts := append([]string{host}, wildcardHosts(host, m.PortInsensitive)...)
+	methods := []string{}
+	for _, e := range m.entries {
+		if (e.route.scheme == scheme || e.route.scheme == anyScheme) && containsString(hosts, e.route.host) && !e.options.hideMethods && !containsString(methods, e.route.method) {
+			methods = append(methods, e.route.method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,108 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_AutoOptions_success(t *testing.T) {
+	m := &mux.Mux{AutoOptions: true}
+	h := http.HandlerFunc(emptyHandler)
+	for _, route := range []struct{ method, pattern string }{
+		{http.MethodGet, "http://localhost/users"},
+		{http.MethodPost, "http://localhost/users"},
+		{http.MethodGet, "http://localhost/orders"},
+		{http.MethodGet, "http://localhost/cors"},
+		{http.MethodOptions, "http://localhost/cors?origin"},
+	} {
+		if err := m.Handle(route.method, route.pattern, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/secret", h, mux.HideMethods()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, url, want string
+	}{
+		{http.MethodOptions, "http://localhost/users", "204 GET, OPTIONS, POST"},
+		{http.MethodDelete, "http://localhost/orders", "405 GET, OPTIONS"},
+		{http.MethodOptions, "http://localhost/cors?origin", "200 "},
+		{http.MethodOptions, "http://localhost/cors", "404 "},
+		{http.MethodOptions, "http://localhost/secret", "404 "},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(test.method, test.url, nil))
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Allow")); test.want != got {
+			t.Fatalf("%s %s: want=%q, got=%q", test.method, test.url, test.want, got)
+		}
+	}
+
+	if want, got := "[OPTIONS+http://localhost/cors?origin]", fmt.Sprint(m.OptionsOverrides()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_AsteriskOptions_success(t *testing.T) {
+	m := &mux.Mux{AutoOptions: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/users", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "//localhost/users", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodDelete, "http://localhost/secret", http.HandlerFunc(emptyHandler), mux.HideMethods()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPut, "http://other.localhost/users", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	asterisk := func() *http.Request {
+		r := httptest.NewRequest(http.MethodOptions, "*", nil)
+		r.Host = "localhost"
+		return r
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, asterisk())
+	if want, got := "204 GET, OPTIONS, POST", fmt.Sprint(rr.Code, " ", rr.Header().Get("Allow")); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	m.AsteriskOptionsHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", "GET")
+		w.WriteHeader(http.StatusOK)
+	})
+	rr = httptest.NewRecorder()
+	m.ServeHTTP(rr, asterisk())
+	if want, got := "200 GET", fmt.Sprint(rr.Code, " ", rr.Header().Get("Allow")); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	m.AsteriskOptionsHandler, m.AutoOptions = nil, false
+	rr = httptest.NewRecorder()
+	m.ServeHTTP(rr, asterisk())
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("want=404, got=%d", rr.Code)
+	}
+}
@@ -0,0 +1,105 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Errors returned by the SameOrigin and CORS options.
+var (
+	//ErrOriginMustBeValid is returned by Handle method when the SameOrigin or CORS options receive an origin that is not a scheme://host[:port] URL.
+	ErrOriginMustBeValid = errors.New("mux: invalid origin")
+)
+
+//sameOriginProtection is the mechanism declared by the SameOrigin option, reported by RouteInfo.Protections.
+const sameOriginProtection = "same-origin"
+
+//SameOrigin makes the route reject cross-origin requests with unsafe methods (POST, PUT, PATCH, DELETE and extension methods) with http.StatusForbidden.
+//
+//The request Origin header, or the Referer header when there is no Origin, must be the origin of the request itself (its scheme and Host header)
+//or one of the trusted origins. Eg: mux.SameOrigin("https://admin.example.com"). Requests with neither header are rejected.
+//
+//It complements CSRF tokens, and declares the "same-origin" mechanism (See the Protected option). It is usually set in a Group next to the routes it protects.
+//
+//Errors
+//
+//• mux.ErrOriginMustBeValid
+func SameOrigin(trusted ...string) RouteOption {
+	return func(o *routeOptions) error {
+		for _, origin := range trusted {
+			origin, err := parseOrigin(origin)
+			if err != nil {
+				return err
+			}
+			o.trustedOrigins = append(o.trustedOrigins, origin)
+		}
+		o.sameOrigin = true
+		o.protections = append(o.protections, sameOriginProtection)
+		return nil
+	}
+}
+
+//parseOrigin validates a scheme://host[:port] origin and normalizes it.
+func parseOrigin(origin string) (string, error) {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || strings.TrimSuffix(u.Path, "/") != "" || u.RawQuery != "" {
+		return "", ErrOriginMustBeValid
+	}
+	return normalizeOrigin(u.Scheme, u.Host), nil
+}
+
+//normalizeOrigin formats an origin with lowercase scheme and host, without the default port.
+func normalizeOrigin(scheme, host string) string {
+	scheme, host = strings.ToLower(scheme), strings.ToLower(host)
+	return scheme + "://" + strings.TrimSuffix(host, ":"+defaultPort(scheme))
+}
+
+//checkOrigin creates a handler that rejects cross-origin requests with unsafe methods before calling the next one.
+func checkOrigin(trusted []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isUnsafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		source := r.Header.Get("Origin")
+		if source == "" {
+			source = r.Header.Get("Referer")
+		}
+		u, err := url.Parse(source)
+		if source == "" || err != nil || u.Host == "" {
+			renderError(w, r, http.StatusForbidden, "The request origin is missing.")
+			return
+		}
+
+		scheme := "http"
+		if m, err := Get(r); err == nil {
+			scheme = m.requestScheme(r)
+		} else if r.TLS != nil {
+			scheme = "https"
+		}
+		origin := normalizeOrigin(u.Scheme, u.Host)
+		if origin != normalizeOrigin(scheme, r.Host) && !containsString(trusted, origin) {
+			renderError(w, r, http.StatusForbidden, "The request origin is not allowed.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
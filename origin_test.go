@@ -0,0 +1,76 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_SameOrigin_success(t *testing.T) {
+	m := &mux.Mux{}
+	admin := m.Group(mux.SameOrigin("https://Console.example.com:443"))
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		if err := admin.Handle(method, "https://example.com/admin", http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		method, origin, referer string
+		want                    int
+	}{
+		{http.MethodGet, "", "", http.StatusOK},
+		{http.MethodPost, "https://example.com", "", http.StatusOK},
+		{http.MethodPost, "https://example.com:443", "", http.StatusOK},
+		{http.MethodPost, "https://console.example.com", "", http.StatusOK},
+		{http.MethodPost, "", "https://example.com/admin/form", http.StatusOK},
+		{http.MethodPost, "https://evil.example", "https://example.com/admin", http.StatusForbidden},
+		{http.MethodPost, "http://example.com", "", http.StatusForbidden},
+		{http.MethodPost, "null", "", http.StatusForbidden},
+		{http.MethodPost, "", "", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "https://example.com/admin", nil)
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		if test.referer != "" {
+			req.Header.Set("Referer", test.referer)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if test.want != rr.Code {
+			t.Fatalf("%s %q %q: want=%d, got=%d", test.method, test.origin, test.referer, test.want, rr.Code)
+		}
+	}
+
+	if want, got := "[]", fmt.Sprint(m.UnprotectedRoutes()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Handle_failOriginMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	for _, origin := range []string{"example.com", "ftp://example.com", "https://example.com/path", "https://"} {
+		if err := m.Handle(http.MethodPost, "http://localhost/", http.HandlerFunc(emptyHandler), mux.SameOrigin(origin)); err != mux.ErrOriginMustBeValid {
+			t.Fatalf("%q: expected: mux.ErrOriginMustBeValid", origin)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"net"
+	"net/http"
+)
+
+//URLParts holds the values a Mux used to match a request, returned by RequestParts function.
+type URLParts struct {
+	//Scheme is "http" or "https", as resolved by the Mux (See Mux.AssumeScheme).
+	Scheme string
+	//Host is the lowercase request host, without the port, after host aliases were resolved (See Mux.AliasHost).
+	Host string
+	//Port is the request port, or the default port of the scheme when the request host has none.
+	Port string
+	//PathSegments are the escaped request path segments, converted to the Mux.PathCollation.
+	PathSegments []string
+}
+
+//RequestParts retrieves the scheme, host, port and path segments used to match a request dispatched by a Mux,
+//so handlers building absolute URLs agree with the routing decisions.
+//
+//Possible error returns:
+//
+//• mux.ErrRequestMustHaveContext
+func RequestParts(r *http.Request) (URLParts, error) {
+	m, err := Get(r)
+	if err != nil {
+		return URLParts{}, err
+	}
+	m.entriesLock.RLock()
+	host := m.routingHost(r.Host)
+	m.entriesLock.RUnlock()
+
+	parts := URLParts{Scheme: m.requestScheme(r), Host: host, PathSegments: m.requestPathSegs(r)}
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		parts.Host, parts.Port = h, port
+	}
+	if parts.Port == "" {
+		parts.Port = defaultPort(parts.Scheme)
+	}
+	return parts, nil
+}
+
+//BaseURL returns the scheme, host and port as an URL prefix, omitting the default port of the scheme. Eg: https://example.com or http://localhost:8080 .
+func (p URLParts) BaseURL() string {
+	if p.Port == defaultPort(p.Scheme) {
+		return p.Scheme + "://" + p.Host
+	}
+	return p.Scheme + "://" + net.JoinHostPort(p.Host, p.Port)
+}
+
+//defaultPort returns the default port of a scheme.
+func defaultPort(scheme string) string {
+	if scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
@@ -0,0 +1,63 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestRequestParts_success(t *testing.T) {
+	m := &mux.Mux{AssumeScheme: "https"}
+	got := ""
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts, err := mux.RequestParts(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = fmt.Sprint(parts.Scheme, " ", parts.Host, " ", parts.Port, " ", parts.PathSegments, " ", parts.BaseURL())
+	})
+	for _, pattern := range []string{"https://example.com/users/{id}", "https://example.com:8443/users/{id}"} {
+		if err := m.Handle(http.MethodGet, pattern, handler); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.AliasHost("www.example.com", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url, want string
+	}{
+		{"http://WWW.Example.com/users/1", "https example.com 443 [users 1] https://example.com"},
+		{"http://example.com:8443/users/2", "https example.com 8443 [users 2] https://example.com:8443"},
+	}
+	for _, test := range tests {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, test.url, nil))
+		if test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestRequestParts_failRequestMustHaveContext(t *testing.T) {
+	if _, err := mux.RequestParts(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err != mux.ErrRequestMustHaveContext {
+		t.Fatal("expected: mux.ErrRequestMustHaveContext")
+	}
+}
@@ -0,0 +1,97 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+//UpdatePlan is the preview of a bulk route update, returned by the dry runs PlanAll and Syncer.Plan methods, so operators can review configuration pushes before applying them.
+type UpdatePlan struct {
+	//Additions are the routes that would be registered.
+	Additions []RouteInfo
+	//Removals are the existing routes that would be removed.
+	Removals []RouteInfo
+	//Conflicts are all the conflicts that would make the update fail, between added and kept routes or among added routes.
+	Conflicts []*ConflictError
+}
+
+//PlanAll is a dry run of HandleAll method: it validates the routes and returns the routes it would register and all the conflicts, without touching the routing table.
+//
+//Errors
+//
+//• Any error returned by Handle method, except conflicts.
+func (m *Mux) PlanAll(specs []RouteSpec) (UpdatePlan, error) {
+	return m.plan(specs, nil)
+}
+
+//plan previews the removal of the routes of the removals specs (when they exist) followed by the registration of the additions specs.
+func (m *Mux) plan(additions, removals []RouteSpec) (UpdatePlan, error) {
+	added, err := m.newPlanEntries(additions)
+	if err != nil {
+		return UpdatePlan{}, err
+	}
+	removed, err := m.newPlanEntries(removals)
+	if err != nil {
+		return UpdatePlan{}, err
+	}
+
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	for _, e := range added {
+		if !m.allowsMethod(e.route.method) {
+			return UpdatePlan{}, ErrMethodMustBeValid
+		}
+		if err := m.checkWildcardHost(e.route); err != nil {
+			return UpdatePlan{}, err
+		}
+	}
+	plan := UpdatePlan{}
+	kept := make(muxEntries, 0, len(m.entries))
+	for _, e := range m.entries {
+		if containsEntryRoute(removed, e.route) {
+			plan.Removals = append(plan.Removals, newRouteInfo(e))
+			continue
+		}
+		kept = append(kept, e)
+	}
+	for _, e := range added {
+		plan.Additions = append(plan.Additions, newRouteInfo(e))
+	}
+	//The conflicts are found as insertEntries method finds them.
+	_, plan.Conflicts = mergeEntries(kept, added)
+	return plan, nil
+}
+
+//newPlanEntries validates the specs and converts them to entries, as HandleAll method would.
+func (m *Mux) newPlanEntries(specs []RouteSpec) ([]muxEntry, error) {
+	entries := make([]muxEntry, 0, len(specs))
+	for _, s := range specs {
+		e, err := newHandleEntry(s.Method, s.URLPattern, s.Handler, s.Options)
+		if err != nil {
+			return nil, err
+		}
+		m.collateRoute(e.route)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+//containsEntryRoute tests if any entry has the same route, as RemoveHandler method finds it.
+func containsEntryRoute(entries []muxEntry, route *muxRoute) bool {
+	for _, e := range entries {
+		if compareStaticRoutes(e.route, route) == 0 {
+			return true
+		}
+	}
+	return false
+}
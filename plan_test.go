@@ -0,0 +1,103 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_PlanAll_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler), mux.Owner("team-a")); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := m.PlanAll([]mux.RouteSpec{
+		{Method: http.MethodGet, URLPattern: "http://localhost/a", Handler: http.HandlerFunc(emptyHandler)},
+		{Method: http.MethodGet, URLPattern: "http://localhost/b", Handler: http.HandlerFunc(emptyHandler)},
+		{Method: http.MethodGet, URLPattern: "http://localhost/b/", Handler: http.HandlerFunc(emptyHandler)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Additions) != 3 || len(plan.Removals) != 0 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(plan.Conflicts) != 2 || plan.Conflicts[0].ExistingOwner != "team-a" || plan.Conflicts[1].ExistingRoute != "GET+http://localhost/b" {
+		t.Fatalf("unexpected conflicts: %v", plan.Conflicts)
+	}
+	if len(m.Routes()) != 1 {
+		t.Fatal("expected: the routing table untouched")
+	}
+}
+
+func TestMux_PlanAll_successConflictsAsHandleAll(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/items?id={:/^a/}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	specs := []mux.RouteSpec{
+		{Method: http.MethodGet, URLPattern: "http://localhost/items?id=zzz", Handler: http.HandlerFunc(emptyHandler)},
+		{Method: http.MethodGet, URLPattern: "http://localhost/items?id=abc", Handler: http.HandlerFunc(emptyHandler)},
+	}
+	plan, err := m.PlanAll(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Route != "GET+http://localhost/items?id=abc" {
+		t.Fatalf("unexpected conflicts: %v", plan.Conflicts)
+	}
+	if err := m.HandleAll(specs); err != mux.ErrRouteMustNotConflict {
+		t.Fatalf("expected: mux.ErrRouteMustNotConflict, got: %v", err)
+	}
+}
+
+func TestMux_PlanAll_failHandlerMustBeNotNil(t *testing.T) {
+	m := &mux.Mux{}
+	if _, err := m.PlanAll([]mux.RouteSpec{{Method: http.MethodGet, URLPattern: "http://localhost/a"}}); err != mux.ErrHandlerMustBeNotNil {
+		t.Fatalf("expected: mux.ErrHandlerMustBeNotNil, got: %v", err)
+	}
+}
+
+func TestSyncer_Plan_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/c", textHandler("static")); err != nil {
+		t.Fatal(err)
+	}
+	source := &testSource{}
+	s := &mux.Syncer{Mux: m, Source: source}
+	source.set(sourceRoute("a", "1", "http://localhost/a", "a1"), sourceRoute("b", "1", "http://localhost/b", "b1"))
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	source.set(sourceRoute("a", "2", "http://localhost/a", "a2"), sourceRoute("c", "1", "http://localhost/c", "c1"))
+	plan, err := s.Plan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Removals) != 2 || len(plan.Additions) != 2 || len(plan.Conflicts) != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan.Conflicts[0].Route != "GET+http://localhost/c" {
+		t.Fatalf("unexpected conflict: %v", plan.Conflicts[0])
+	}
+	if serveText(m, "http://localhost/a") != "a1" || serveText(m, "http://localhost/b") != "b1" {
+		t.Fatal("expected: the routing table untouched")
+	}
+}
@@ -0,0 +1,58 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+)
+
+//Problem is a RFC 7807 problem details object, passed to ErrorRenderer implementations and sent as JSON by ProblemErrors.
+type Problem struct {
+	//Type is a URI reference identifying the problem type. Mux generated problems use "about:blank".
+	Type string `json:"type"`
+	//Title is the status text. Eg: "Not Found".
+	Title string `json:"title"`
+	//Status is the HTTP status code.
+	Status int `json:"status"`
+	//Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	//Instance is the request path.
+	Instance string `json:"instance,omitempty"`
+	//RequestID is the id assigned to the request by Mux.RequestIDs, or else its X-Request-Id header, so the problem can be correlated with logs.
+	RequestID string `json:"requestId,omitempty"`
+	//AllowedMethods are the methods of the requested path, in 405 problems.
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+}
+
+//newProblem creates the problem details of a request answered with a status code.
+func newProblem(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestIDOrHeader(r),
+	}
+}
+
+//requestIDOrHeader returns the id assigned to the request, or its X-Request-Id header if no id was assigned.
+func requestIDOrHeader(r *http.Request) string {
+	if id := RequestID(r); id != "" {
+		return id
+	}
+	return r.Header.Get(defaultRequestIDHeader)
+}
@@ -0,0 +1,50 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_ProblemDetails_success(t *testing.T) {
+	m := &mux.Mux{ProblemDetails: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "/users", `404 application/problem+json {"type":"about:blank","title":"Not Found","status":404,"detail":"No route matches the request.","instance":"/users","requestId":"abc"}`},
+		{http.MethodDelete, "/orders", `405 application/problem+json {"type":"about:blank","title":"Method Not Allowed","status":405,"detail":"The request method is not allowed for the requested path.","instance":"/orders","requestId":"abc","allowedMethods":["GET","POST"]}`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "http://localhost"+test.path, nil)
+		req.Header.Set("X-Request-Id", "abc")
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Content-Type"), " ", rr.Body.String()); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	//Used in request contexts.
+	ctxProxyErrorValue = "gitlab.com/gopherburrow/mux ProxyError"
+	//defaultMaxRetryBodySize is the body size limit used when Proxy.MaxRetryBodySize is zero.
+	defaultMaxRetryBodySize = 64 * 1024
+	//defaultHealthCheckInterval and defaultHealthCheckTimeout are used when the HealthCheck fields are zero.
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 2 * time.Second
+)
+
+//Errors returned by Proxy method.
+var (
+	//ErrProxyMustBeValid is returned by Proxy method (or Handle method, for a *Proxy handler) when the Proxy has no backends, a backend URL is not absolute,
+	//a weight, Retries or a backend timeout is negative, the HealthCheck has no Path, gateway timeouts apply to a backend Transport that is not an *http.Transport,
+	//or the Proxy is registered with different GatewayTimeouts.
+	ErrProxyMustBeValid = errors.New("mux: invalid proxy")
+	//ErrGatewayTimeoutsMustBeValid is returned by Gateway option when a timeout is negative.
+	ErrGatewayTimeoutsMustBeValid = errors.New("mux: gateway timeouts must be valid")
+)
+
+//The key used to report backend errors from the reverse proxy to the retry loop.
+var ctxProxyError = ctxType(ctxProxyErrorValue)
+
+//idempotentHTTPMethods are the methods retried by Proxy.
+var idempotentHTTPMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace}
+
+//Backend is an upstream server of a Proxy.
+type Backend struct {
+	//URL is the base URL of the backend, with an optional path prefix. Eg: http://10.0.0.1:8080 . The request path and query are appended to it.
+	URL string
+	//Weight is the share of requests sent to the backend, relative to the other healthy backends. If zero, 1 is used.
+	Weight int
+	//Transport sends the requests to the backend. If nil, http.DefaultTransport is used.
+	//It must be an *http.Transport when gateway timeouts apply to the backend, as they are set in a clone of it.
+	Transport http.RoundTripper
+	//Timeouts override, field by field, the GatewayTimeouts of the route (See `mux.Gateway` option) for this backend.
+	Timeouts  GatewayTimeouts
+	target    *url.URL
+	proxy     *httputil.ReverseProxy
+	rt        http.RoundTripper
+	unhealthy int32
+}
+
+//Healthy tells if the backend passed its last health check. Backends are healthy until a check fails.
+func (b *Backend) Healthy() bool {
+	return atomic.LoadInt32(&b.unhealthy) == 0
+}
+
+//weight returns the weight of the backend.
+func (b *Backend) weight() int {
+	if b.Weight == 0 {
+		return 1
+	}
+	return b.Weight
+}
+
+//transport returns the transport used to reach the backend, applying the gateway timeouts to a clone of it.
+//It returns nil if there are timeouts to apply and the transport is not an *http.Transport.
+func (b *Backend) transport(route GatewayTimeouts) http.RoundTripper {
+	base := b.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := route.override(b.Timeouts)
+	if t == (GatewayTimeouts{}) {
+		return base
+	}
+	ht, ok := base.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	ht = ht.Clone()
+	if t.Dial > 0 {
+		dial := ht.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		ht.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(ctx, t.Dial)
+			defer cancel()
+			return dial(ctx, network, addr)
+		}
+	}
+	if t.ResponseHeader > 0 {
+		ht.ResponseHeaderTimeout = t.ResponseHeader
+	}
+	if t.Idle > 0 {
+		ht.IdleConnTimeout = t.Idle
+	}
+	return ht
+}
+
+//GatewayTimeouts limit the phases of the exchanges between a Proxy and its backends, apart from the time budget of the whole request (See `mux.ContextTimeout` option).
+//
+//A backend exceeding them is failed as unreachable, so idempotent requests are retried in other backends. Zero fields are not set.
+type GatewayTimeouts struct {
+	//Dial limits the establishment of connections to the backend.
+	Dial time.Duration
+	//ResponseHeader limits the wait for the response headers, after the request is written. Mapped to http.Transport ResponseHeaderTimeout.
+	ResponseHeader time.Duration
+	//Idle is the time idle keep-alive connections to the backend are kept open. Mapped to http.Transport IdleConnTimeout.
+	Idle time.Duration
+}
+
+//override returns the timeouts with the non-zero fields of o replacing its own.
+func (t GatewayTimeouts) override(o GatewayTimeouts) GatewayTimeouts {
+	if o.Dial != 0 {
+		t.Dial = o.Dial
+	}
+	if o.ResponseHeader != 0 {
+		t.ResponseHeader = o.ResponseHeader
+	}
+	if o.Idle != 0 {
+		t.Idle = o.Idle
+	}
+	return t
+}
+
+//valid tests if no timeout is negative.
+func (t GatewayTimeouts) valid() bool {
+	return t.Dial >= 0 && t.ResponseHeader >= 0 && t.Idle >= 0
+}
+
+//Gateway sets the default GatewayTimeouts of the backends of a Proxy route. Each Backend can override them with its Timeouts.
+//
+//A Proxy registered in many routes must have the same GatewayTimeouts in all of them.
+//
+//Errors
+//
+//• mux.ErrGatewayTimeoutsMustBeValid
+func Gateway(t GatewayTimeouts) RouteOption {
+	return func(o *routeOptions) error {
+		if !t.valid() {
+			return ErrGatewayTimeoutsMustBeValid
+		}
+		o.gatewayTimeouts = t
+		return nil
+	}
+}
+
+//HealthCheck configures the active health checking of the Proxy backends.
+type HealthCheck struct {
+	//Path is requested (GET) in each backend. Backends answering with a status below 400 are healthy. Eg: /healthz .
+	Path string
+	//Interval is the time between checks. If zero, 10 seconds are used.
+	Interval time.Duration
+	//Timeout limits each check. If zero, 2 seconds are used.
+	Timeout time.Duration
+}
+
+//Proxy is a reverse proxy route handler, registered with Proxy method, balancing requests among weighted backends.
+//
+//Requests with idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE and TRACE) are retried in other backends when a backend cannot be reached,
+//and backends failing the HealthCheck are removed from the weighted set until they recover.
+//Requests are answered with http.StatusBadGateway when every attempt fails, and with http.StatusServiceUnavailable when there is no healthy backend.
+//
+//The health checks run while the Proxy has registered routes.
+type Proxy struct {
+	//Backends are the upstream servers.
+	Backends []*Backend
+	//Retries is the number of additional attempts of idempotent requests, each in a backend not tried yet.
+	Retries int
+	//MaxRetryBodySize is the maximum number of bytes of request bodies buffered for retries. Larger requests are not retried. If zero, 64KiB are buffered.
+	MaxRetryBodySize int64
+	//HealthCheck enables active health checking. If nil, backends are always healthy.
+	HealthCheck *HealthCheck
+	lock        sync.Mutex
+	routes      int
+	timeouts    GatewayTimeouts
+	stop        chan struct{}
+}
+
+//Proxy registers a reverse proxy route, forwarding the requests to the Proxy backends.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+//
+//Errors
+//
+//• mux.ErrProxyMustBeValid
+func (m *Mux) Proxy(httpMethod string, urlPattern string, p *Proxy, opts ...RouteOption) error {
+	if p == nil {
+		return ErrProxyMustBeValid
+	}
+	return m.Handle(httpMethod, urlPattern, p, opts...)
+}
+
+//OnRegister implements RouteLifecycle, validating the Proxy and starting the health checks with its first route.
+func (p *Proxy) OnRegister(route RouteInfo) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.routes > 0 && route.GatewayTimeouts != p.timeouts {
+		return ErrProxyMustBeValid
+	}
+	if err := p.init(route.GatewayTimeouts); err != nil {
+		return err
+	}
+	p.timeouts = route.GatewayTimeouts
+	p.routes++
+	if p.routes == 1 && p.HealthCheck != nil {
+		p.stop = make(chan struct{})
+		go p.checkHealth(p.stop)
+	}
+	return nil
+}
+
+//OnRemove implements RouteLifecycle, stopping the health checks with the removal of its last route.
+func (p *Proxy) OnRemove(route RouteInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.routes--
+	if p.routes == 0 && p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+//init validates the Proxy and prepares its backends with the route gateway timeouts. The caller must hold the lock.
+func (p *Proxy) init(timeouts GatewayTimeouts) error {
+	if len(p.Backends) == 0 || p.Retries < 0 || p.MaxRetryBodySize < 0 || (p.HealthCheck != nil && p.HealthCheck.Path == "") {
+		return ErrProxyMustBeValid
+	}
+	for _, b := range p.Backends {
+		if b == nil || b.Weight < 0 || !b.Timeouts.valid() {
+			return ErrProxyMustBeValid
+		}
+		if b.proxy != nil && p.routes > 0 {
+			continue
+		}
+		target, err := url.Parse(b.URL)
+		if err != nil || !target.IsAbs() || target.Host == "" {
+			return ErrProxyMustBeValid
+		}
+		rt := b.transport(timeouts)
+		if rt == nil {
+			return ErrProxyMustBeValid
+		}
+		b.target = target
+		b.rt = rt
+		b.proxy = &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(target)
+				pr.SetXForwarded()
+			},
+			Transport: rt,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				if backendErr, ok := r.Context().Value(ctxProxyError).(*error); ok {
+					*backendErr = err
+				}
+			},
+		}
+	}
+	return nil
+}
+
+//ServeHTTP forwards the request to a healthy backend, retrying idempotent requests in other backends.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	attempts := 1
+	var body []byte
+	if p.Retries > 0 && containsString(idempotentHTTPMethods, r.Method) {
+		var complete bool
+		if body, complete = p.bufferBody(r); complete {
+			attempts += p.Retries
+		}
+	}
+
+	tried := make([]*Backend, 0, attempts)
+	for len(tried) < attempts {
+		b := p.pick(tried)
+		if b == nil {
+			break
+		}
+		tried = append(tried, b)
+		if body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		var backendErr error
+		b.proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxProxyError, &backendErr)))
+		if backendErr == nil {
+			return
+		}
+		//The client went away, so there is nobody to retry for.
+		if r.Context().Err() != nil {
+			return
+		}
+	}
+	if len(tried) == 0 {
+		renderError(w, r, http.StatusServiceUnavailable, "There is no healthy backend.")
+		return
+	}
+	renderError(w, r, http.StatusBadGateway, "The backend could not be reached.")
+}
+
+//bufferBody reads the request body so it can be sent again. If it is larger than MaxRetryBodySize, the body is given back unbuffered and complete is false.
+func (p *Proxy) bufferBody(r *http.Request) (body []byte, complete bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	max := p.MaxRetryBodySize
+	if max == 0 {
+		max = defaultMaxRetryBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, max+1))
+	if err != nil || int64(len(body)) > max {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return nil, false
+	}
+	r.Body.Close()
+	return body, true
+}
+
+//pick selects a healthy backend not tried yet, randomly according to the weights. It returns nil if there is none.
+func (p *Proxy) pick(tried []*Backend) *Backend {
+	total := 0
+	for _, b := range p.Backends {
+		if b.Healthy() && !containsBackend(tried, b) {
+			total += b.weight()
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	n := rand.Intn(total)
+	for _, b := range p.Backends {
+		if !b.Healthy() || containsBackend(tried, b) {
+			continue
+		}
+		if n -= b.weight(); n < 0 {
+			return b
+		}
+	}
+	return nil
+}
+
+//containsBackend tests if a backend is in a slice.
+func containsBackend(backends []*Backend, b *Backend) bool {
+	for _, c := range backends {
+		if c == b {
+			return true
+		}
+	}
+	return false
+}
+
+//checkHealth checks the backends periodically, until stop is closed.
+func (p *Proxy) checkHealth(stop <-chan struct{}) {
+	interval := p.HealthCheck.Interval
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, b := range p.Backends {
+			p.probe(b)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+//probe checks the health of a backend.
+func (p *Proxy) probe(b *Backend) {
+	timeout := p.HealthCheck.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	healthy := false
+	target := *b.target
+	target.Path = target.JoinPath(p.HealthCheck.Path).Path
+	if req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil); err == nil {
+		if resp, err := b.rt.RoundTrip(req); err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			healthy = resp.StatusCode < 400
+		}
+	}
+	if healthy {
+		atomic.StoreInt32(&b.unhealthy, 0)
+	} else {
+		atomic.StoreInt32(&b.unhealthy, 1)
+	}
+}
@@ -0,0 +1,221 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func newTestBackend(name string, healthy bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			if !healthy {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprint(w, name, " ", r.Method, " ", r.URL.RequestURI(), " ", string(body))
+	}))
+}
+
+//newDeadBackend returns the URL of a server that refuses connections.
+func newDeadBackend() string {
+	s := httptest.NewServer(http.HandlerFunc(emptyHandler))
+	s.Close()
+	return s.URL
+}
+
+//roundTripperFunc is a custom http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestMux_Proxy_successRetries(t *testing.T) {
+	alive := newTestBackend("alive", true)
+	defer alive.Close()
+	m := &mux.Mux{}
+	p := &mux.Proxy{
+		Backends: []*mux.Backend{{URL: newDeadBackend(), Weight: 1000}, {URL: alive.URL}},
+		Retries:  1,
+	}
+	if err := m.Proxy(http.MethodPut, "http://localhost/api/{*}", p); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Proxy(http.MethodPost, "http://localhost/submit", p); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "http://localhost/api/items/1?v=2", strings.NewReader("data")))
+	if want, got := "200 alive PUT /api/items/1?v=2 data", fmt.Sprint(rr.Code, " ", rr.Body.String()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	//POST is not idempotent, so it is not retried. The dead backend is picked almost always.
+	codes := map[int]bool{}
+	for i := 0; i < 20; i++ {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "http://localhost/submit", strings.NewReader("data")))
+		codes[rr.Code] = true
+	}
+	if !codes[http.StatusBadGateway] {
+		t.Fatal("expected: http.StatusBadGateway")
+	}
+}
+
+func TestMux_Proxy_successHealthCheck(t *testing.T) {
+	healthy, sick := newTestBackend("healthy", true), newTestBackend("sick", false)
+	defer healthy.Close()
+	defer sick.Close()
+	m := &mux.Mux{}
+	p := &mux.Proxy{
+		Backends:    []*mux.Backend{{URL: sick.URL}, {URL: healthy.URL}},
+		HealthCheck: &mux.HealthCheck{Path: "/healthz", Interval: 10 * time.Millisecond},
+	}
+	if err := m.Proxy(http.MethodGet, "http://localhost/{*}", p); err != nil {
+		t.Fatal(err)
+	}
+	defer m.RemoveHandler(http.MethodGet, "http://localhost/{*}")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Backends[0].Healthy() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/x", nil))
+		if want, got := "healthy GET /x ", rr.Body.String(); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+	}
+}
+
+func TestMux_Proxy_successNoHealthyBackend(t *testing.T) {
+	sick := newTestBackend("sick", false)
+	defer sick.Close()
+	m := &mux.Mux{}
+	p := &mux.Proxy{
+		Backends:    []*mux.Backend{{URL: sick.URL}},
+		HealthCheck: &mux.HealthCheck{Path: "/healthz", Interval: 10 * time.Millisecond},
+	}
+	if err := m.Proxy(http.MethodGet, "http://localhost/", p); err != nil {
+		t.Fatal(err)
+	}
+	defer m.RemoveHandler(http.MethodGet, "http://localhost/")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Backends[0].Healthy() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	if want, got := http.StatusServiceUnavailable, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_Proxy_failProxyMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	for _, p := range []*mux.Proxy{
+		nil,
+		{},
+		{Backends: []*mux.Backend{{URL: "/relative"}}},
+		{Backends: []*mux.Backend{{URL: "http://localhost", Weight: -1}}},
+		{Backends: []*mux.Backend{{URL: "http://localhost"}}, Retries: -1},
+		{Backends: []*mux.Backend{{URL: "http://localhost"}}, HealthCheck: &mux.HealthCheck{}},
+	} {
+		if err := m.Proxy(http.MethodGet, "http://localhost/", p); err != mux.ErrProxyMustBeValid {
+			t.Fatal("expected: mux.ErrProxyMustBeValid")
+		}
+	}
+}
+
+func TestMux_Proxy_successGatewayTimeouts(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, "slow")
+	}))
+	defer slow.Close()
+	fast := newTestBackend("fast", true)
+	defer fast.Close()
+
+	m := &mux.Mux{}
+	slowBackend := &mux.Backend{URL: slow.URL, Weight: 1000}
+	p := &mux.Proxy{Backends: []*mux.Backend{slowBackend, {URL: fast.URL}}, Retries: 1}
+	if err := m.Proxy(http.MethodGet, "http://localhost/{*}", p, mux.Gateway(mux.GatewayTimeouts{ResponseHeader: 50 * time.Millisecond})); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (mux.GatewayTimeouts{ResponseHeader: 50 * time.Millisecond}), m.Routes()[0].GatewayTimeouts; want != got {
+		t.Fatalf("want=%v, got=%v", want, got)
+	}
+
+	//The slow backend misses the response header timeout, so the request is retried in the fast one.
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/a", nil))
+	if want, got := "200 fast GET /a ", fmt.Sprint(rr.Code, " ", rr.Body.String()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	//The backend timeouts override the route ones.
+	m.RemoveHandler(http.MethodGet, "http://localhost/{*}")
+	slowBackend.Timeouts = mux.GatewayTimeouts{ResponseHeader: time.Second}
+	p.Retries = 0
+	if err := m.Proxy(http.MethodGet, "http://localhost/{*}", p, mux.Gateway(mux.GatewayTimeouts{ResponseHeader: 50 * time.Millisecond})); err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	for i := 0; i < 10 && rr.Body.String() != "slow"; i++ {
+		rr = httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/a", nil))
+	}
+	if want, got := "200 slow", fmt.Sprint(rr.Code, " ", rr.Body.String()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Proxy_failGatewayTimeoutsMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	p := &mux.Proxy{Backends: []*mux.Backend{{URL: "http://10.0.0.1"}}}
+	if err := m.Proxy(http.MethodGet, "http://localhost/a", p, mux.Gateway(mux.GatewayTimeouts{Dial: -1})); err != mux.ErrGatewayTimeoutsMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrGatewayTimeoutsMustBeValid, err)
+	}
+	if err := m.Proxy(http.MethodGet, "http://localhost/a", p, mux.Gateway(mux.GatewayTimeouts{Dial: time.Second})); err != nil {
+		t.Fatal(err)
+	}
+	//A shared Proxy must have the same timeouts in every route.
+	if err := m.Proxy(http.MethodGet, "http://localhost/b", p, mux.Gateway(mux.GatewayTimeouts{Dial: 2 * time.Second})); err != mux.ErrProxyMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrProxyMustBeValid, err)
+	}
+	//Timeouts cannot be applied to custom transports.
+	custom := &mux.Proxy{Backends: []*mux.Backend{{URL: "http://10.0.0.1", Transport: roundTripperFunc(nil), Timeouts: mux.GatewayTimeouts{Idle: time.Second}}}}
+	if err := m.Proxy(http.MethodGet, "http://localhost/c", custom); err != mux.ErrProxyMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrProxyMustBeValid, err)
+	}
+	negative := &mux.Proxy{Backends: []*mux.Backend{{URL: "http://10.0.0.1", Timeouts: mux.GatewayTimeouts{Idle: -time.Second}}}}
+	if err := m.Proxy(http.MethodGet, "http://localhost/d", negative); err != mux.ErrProxyMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrProxyMustBeValid, err)
+	}
+}
@@ -0,0 +1,54 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+//Quarantine soft-deletes a route: it stays in the routing table, but its requests are answered with http.StatusGone, without calling the handler.
+//The note (Eg: "Use /v2/orders instead.") is the detail of the rendered problem, shown by the ProblemErrors and HTMLErrors renderers (See `mux.ErrorRenderer`).
+//It covers retired endpoints still called by clients more informatively than a removal, and hits are still counted when Mux.AuditHits is set, so the remaining clients can be found.
+//
+//The route is identified as in RemoveHandler method. A quarantined route can be put back by Unquarantine method, or removed by RemoveHandler method.
+//
+//Errors
+//
+//• Any error returned by RemoveHandler method.
+func (m *Mux) Quarantine(httpMethod, urlPattern, note string, opts ...RouteOption) error {
+	return m.setQuarantine(httpMethod, urlPattern, &note, opts)
+}
+
+//Unquarantine puts a route quarantined by Quarantine method back to its handler.
+//
+//Errors
+//
+//• Any error returned by RemoveHandler method.
+func (m *Mux) Unquarantine(httpMethod, urlPattern string, opts ...RouteOption) error {
+	return m.setQuarantine(httpMethod, urlPattern, nil, opts)
+}
+
+//setQuarantine sets the quarantine note of an existing route. A nil note puts the route back.
+func (m *Mux) setQuarantine(httpMethod, urlPattern string, note *string, opts []RouteOption) error {
+	route, err := m.newLookupRoute(httpMethod, urlPattern, opts)
+	if err != nil {
+		return err
+	}
+	m.entriesLock.Lock()
+	defer m.entriesLock.Unlock()
+	i, err := m.indexOf(route)
+	if err != nil {
+		return err
+	}
+	m.entries[i].quarantine = note
+	return nil
+}
@@ -0,0 +1,65 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Quarantine_success(t *testing.T) {
+	m := &mux.Mux{AuditHits: true, ProblemDetails: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/v1/orders/{id}", newTestHandler("v1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Quarantine(http.MethodGet, "http://localhost/v1/orders/{id}", "Use /v2/orders instead."); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/v1/orders/1", nil))
+	if rr.Code != http.StatusGone || !strings.Contains(rr.Body.String(), "Use /v2/orders instead.") {
+		t.Fatalf("expected: 410 with the note, got: %d %q", rr.Code, rr.Body.String())
+	}
+	ri := m.Routes()[0]
+	if !ri.Quarantined || ri.QuarantineNote != "Use /v2/orders instead." || ri.Hits != 1 {
+		t.Fatalf("unexpected route info: %+v", ri)
+	}
+
+	if err := m.Unquarantine(http.MethodGet, "http://localhost/v1/orders/{id}"); err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/v1/orders/1", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != "v1" {
+		t.Fatalf("expected: the handler, got: %d %q", rr.Code, rr.Body.String())
+	}
+	if m.Routes()[0].Quarantined {
+		t.Fatal("expected: the route live")
+	}
+}
+
+func TestMux_Quarantine_failRouteMustExist(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Quarantine(http.MethodGet, "http://localhost/v1/orders", "Gone."); err != mux.ErrRouteMustExist {
+		t.Fatalf("expected: mux.ErrRouteMustExist, got: %v", err)
+	}
+	if err := m.Unquarantine("", "http://localhost/v1/orders"); err != mux.ErrMethodMustBeValid {
+		t.Fatalf("expected: mux.ErrMethodMustBeValid, got: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"container/list"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//ErrQueueMustBeValid is returned by Handle method when the Queue option size or maximum wait is not positive.
+var ErrQueueMustBeValid = errors.New("mux: queue size and max wait must be positive")
+
+//routeQueue holds the Queue option parameters.
+type routeQueue struct {
+	size    int
+	maxWait time.Duration
+}
+
+//Queue absorbs short bursts on routes limited by the MaxConcurrent option: requests over the limit wait, in arrival order, for a request being served to finish.
+//
+//At most size requests wait, for at most maxWait each. Requests arriving with a full queue, waiting longer than maxWait or whose clients go away are rejected with the Throttling response set by the Throttle option.
+//Without the MaxConcurrent option, the queue has no effect.
+//
+//Errors
+//
+//• mux.ErrQueueMustBeValid
+func Queue(size int, maxWait time.Duration) RouteOption {
+	return func(o *routeOptions) error {
+		if size <= 0 || maxWait <= 0 {
+			return ErrQueueMustBeValid
+		}
+		o.queue = &routeQueue{size: size, maxWait: maxWait}
+		return nil
+	}
+}
+
+//concurrencyLimiter counts the requests being served by a route and parks the excess ones in a FIFO queue.
+type concurrencyLimiter struct {
+	max   int
+	queue routeQueue
+	lock  sync.Mutex
+	//inFlight is the number of requests being served.
+	inFlight int
+	//waiting contains a channel for each parked request, closed when it is handed a slot.
+	waiting list.List
+}
+
+//acquire tries to get a slot to serve a request, waiting in the queue if there is one. The slot must be released if acquire returns true.
+func (l *concurrencyLimiter) acquire(r *http.Request) bool {
+	l.lock.Lock()
+	if l.inFlight < l.max && l.waiting.Len() == 0 {
+		l.inFlight++
+		l.lock.Unlock()
+		return true
+	}
+	if l.waiting.Len() >= l.queue.size {
+		l.lock.Unlock()
+		return false
+	}
+	ready := make(chan struct{})
+	el := l.waiting.PushBack(ready)
+	l.lock.Unlock()
+
+	timer := time.NewTimer(l.queue.maxWait)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return true
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	//The slot may have been handed over while giving up.
+	select {
+	case <-ready:
+		return true
+	default:
+	}
+	l.waiting.Remove(el)
+	return false
+}
+
+//release frees a slot, handing it over to the first parked request, if any.
+func (l *concurrencyLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if el := l.waiting.Front(); el != nil {
+		close(l.waiting.Remove(el).(chan struct{}))
+		return
+	}
+	l.inFlight--
+}
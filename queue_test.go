@@ -0,0 +1,111 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Queue_success(t *testing.T) {
+	m := &mux.Mux{}
+	started, release := make(chan string, 3), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- r.URL.Query().Get("n")
+		<-release
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/reports", handler, mux.MaxConcurrent(1), mux.Queue(2, time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	codes := make(chan int, 3)
+	serve := func(n int) {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, fmt.Sprint("http://localhost/reports?n=", n), nil))
+		codes <- rr.Code
+	}
+	go serve(1)
+	<-started
+	go serve(2)
+	//Wait for the second request to be parked, so the order is deterministic.
+	time.Sleep(20 * time.Millisecond)
+	go serve(3)
+	time.Sleep(20 * time.Millisecond)
+
+	//The queue is full.
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/reports?n=4", nil))
+	if want, got := http.StatusServiceUnavailable, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+
+	release <- struct{}{}
+	if want, got := "2", <-started; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	release <- struct{}{}
+	if want, got := "3", <-started; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	release <- struct{}{}
+	for i := 0; i < 3; i++ {
+		if want, got := http.StatusOK, <-codes; want != got {
+			t.Fatalf("want=%d, got=%d", want, got)
+		}
+	}
+}
+
+func TestMux_Queue_successMaxWait(t *testing.T) {
+	m := &mux.Mux{}
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	throttling := &mux.Throttling{Status: http.StatusTooManyRequests}
+	if err := m.Handle(http.MethodGet, "http://localhost/reports", handler, mux.MaxConcurrent(1), mux.Queue(1, 20*time.Millisecond), mux.Throttle(throttling)); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/reports", nil))
+		close(done)
+	}()
+	<-started
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/reports", nil))
+	close(release)
+	<-done
+	if want, got := http.StatusTooManyRequests, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_Handle_failQueue(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Queue(0, time.Second)); err != mux.ErrQueueMustBeValid {
+		t.Fatal("expected: mux.ErrQueueMustBeValid")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Queue(1, 0)); err != mux.ErrQueueMustBeValid {
+		t.Fatal("expected: mux.ErrQueueMustBeValid")
+	}
+}
@@ -0,0 +1,305 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+//Errors returned by the Record option and Replay method.
+var (
+	//ErrRecorderMustHaveSink is returned by Handle method when the Record option receives a nil Recorder or a Recorder without Sink.
+	ErrRecorderMustHaveSink = errors.New("mux: Recorder must have a Sink")
+)
+
+//defaultRedactedHeaders are the headers removed from recordings when Recorder.RedactHeaders is nil.
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+//defaultMaxRecordedBodySize is the body size limit used when Recorder.MaxBodySize is zero.
+const defaultMaxRecordedBodySize = 64 * 1024
+
+//Recording is a sanitized request/response pair captured by a Recorder.
+type Recording struct {
+	//Time is when the request was received.
+	Time time.Time
+	//Route describes the route that handled the request.
+	Route RouteInfo
+	//Method is the request HTTP method.
+	Method string
+	//URL is the absolute request URL, including scheme and host.
+	URL string
+	//Header contains the request headers, without the redacted ones.
+	Header http.Header
+	//Body contains the request body read by the handler, truncated to Recorder.MaxBodySize.
+	Body []byte
+	//Status is the response HTTP status code.
+	Status int
+	//ResponseHeader contains the response headers, without the redacted ones.
+	ResponseHeader http.Header
+	//ResponseBody contains the response body, truncated to Recorder.MaxBodySize.
+	ResponseBody []byte
+}
+
+//RecordSink stores recordings. Eg: in memory, in files or in a remote service.
+//
+//Record is called concurrently, after the response is written.
+type RecordSink interface {
+	Record(rec Recording)
+}
+
+//Recorder captures request/response pairs of the routes it is attached to by the Record option.
+type Recorder struct {
+	//Sink receives the recordings.
+	Sink RecordSink
+	//RedactHeaders are the names of request and response headers removed from recordings.
+	//If nil, Authorization, Proxy-Authorization, Cookie and Set-Cookie are removed.
+	RedactHeaders []string
+	//MaxBodySize is the maximum number of bytes captured from each body. If zero, 64KiB are captured.
+	MaxBodySize int
+}
+
+//Record attaches a Recorder to a route.
+//
+//Errors
+//
+//• mux.ErrRecorderMustHaveSink
+func Record(rec *Recorder) RouteOption {
+	return func(o *routeOptions) error {
+		if rec == nil || rec.Sink == nil {
+			return ErrRecorderMustHaveSink
+		}
+		o.recorder = rec
+		return nil
+	}
+}
+
+//wrap creates a handler that records the requests served by the next one.
+func (rec *Recorder) wrap(next http.Handler, route RouteInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxBodySize := rec.MaxBodySize
+		if maxBodySize == 0 {
+			maxBodySize = defaultMaxRecordedBodySize
+		}
+
+		//Capture what the handler reads from the request body...
+		reqBody := &limitedBuffer{max: maxBodySize}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = &teeReadCloser{Reader: io.TeeReader(r.Body, reqBody), Closer: r.Body}
+		}
+		//...and what it writes in the response.
+		cw := &captureWriter{ResponseWriter: w, body: limitedBuffer{max: maxBodySize}}
+		start := time.Now()
+		next.ServeHTTP(cw, r)
+		if cw.header == nil {
+			cw.header = w.Header()
+		}
+
+		rec.Sink.Record(Recording{
+			Time:           start,
+			Route:          route,
+			Method:         r.Method,
+			URL:            requestURL(r),
+			Header:         rec.redact(r.Header),
+			Body:           reqBody.Bytes(),
+			Status:         cw.statusCode(),
+			ResponseHeader: rec.redact(cw.header),
+			ResponseBody:   cw.body.Bytes(),
+		})
+	})
+}
+
+//redact clones a header removing the RedactHeaders.
+func (rec *Recorder) redact(h http.Header) http.Header {
+	redacted := rec.RedactHeaders
+	if redacted == nil {
+		redacted = defaultRedactedHeaders
+	}
+	c := http.Header{}
+	for k, vs := range h {
+		c[k] = append([]string(nil), vs...)
+	}
+	for _, k := range redacted {
+		c.Del(k)
+	}
+	return c
+}
+
+//ReplayResult is the response obtained by replaying a Recording.
+type ReplayResult struct {
+	//Recording is the replayed recording.
+	Recording Recording
+	//Status is the HTTP status code of the replayed response.
+	Status int
+	//Header contains the headers of the replayed response.
+	Header http.Header
+	//Body contains the body of the replayed response.
+	Body []byte
+}
+
+//Matches tests if the replayed response has the same status and body of the recorded one.
+func (rr ReplayResult) Matches() bool {
+	return rr.Status == rr.Recording.Status && bytes.Equal(rr.Body, rr.Recording.ResponseBody)
+}
+
+//Replay feeds recordings back through ServeHTTP, so routing changes can be regression tested against recorded traffic.
+//
+//Redacted headers are not replayed.
+func (m *Mux) Replay(recs ...Recording) []ReplayResult {
+	results := make([]ReplayResult, 0, len(recs))
+	for _, rec := range recs {
+		req, err := http.NewRequest(rec.Method, rec.URL, bytes.NewReader(rec.Body))
+		if err != nil {
+			results = append(results, ReplayResult{Recording: rec, Status: http.StatusBadRequest, Header: http.Header{}})
+			continue
+		}
+		for k, vs := range rec.Header {
+			req.Header[k] = append([]string(nil), vs...)
+		}
+		if req.URL.Scheme == "https" {
+			req.TLS = &tls.ConnectionState{}
+		}
+		bw := &bufferWriter{header: http.Header{}}
+		m.ServeHTTP(bw, req)
+		results = append(results, ReplayResult{
+			Recording: rec,
+			Status:    bw.statusCode(),
+			Header:    bw.header,
+			Body:      bw.body.Bytes(),
+		})
+	}
+	return results
+}
+
+//requestURL rebuilds the absolute URL of a request.
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+//limitedBuffer is a buffer that silently discards everything written after max bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+//Write writes up to the buffer limit, but always reports success.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room < len(p) {
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+//teeReadCloser joins a tee reader with the original body closer.
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+//captureWriter is a responseWriter that also captures the response headers and body.
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   limitedBuffer
+}
+
+//WriteHeader captures the status code and the headers.
+func (cw *captureWriter) WriteHeader(statusCode int) {
+	if cw.status == 0 && (statusCode < 100 || statusCode > 199) {
+		cw.status = statusCode
+		cw.header = cw.ResponseWriter.Header().Clone()
+	}
+	cw.ResponseWriter.WriteHeader(statusCode)
+}
+
+//Write captures the body.
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	cw.body.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
+
+//Flush implements `http.Flusher` when the wrapped writer does.
+func (cw *captureWriter) Flush() {
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		if cw.status == 0 {
+			cw.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+//Unwrap returns the wrapped writer, so `http.ResponseController` can reach it.
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+//statusCode returns the captured status code. Handlers that write nothing produce an implicit http.StatusOK.
+func (cw *captureWriter) statusCode() int {
+	if cw.status == 0 {
+		return http.StatusOK
+	}
+	return cw.status
+}
+
+//bufferWriter is an in memory `http.ResponseWriter`.
+type bufferWriter struct {
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+//Header returns the response headers.
+func (bw *bufferWriter) Header() http.Header {
+	return bw.header
+}
+
+//WriteHeader stores the status code.
+func (bw *bufferWriter) WriteHeader(statusCode int) {
+	if bw.status == 0 && (statusCode < 100 || statusCode > 199) {
+		bw.status = statusCode
+	}
+}
+
+//Write stores the body.
+func (bw *bufferWriter) Write(b []byte) (int, error) {
+	if bw.status == 0 {
+		bw.status = http.StatusOK
+	}
+	return bw.body.Write(b)
+}
+
+//statusCode returns the stored status code. Handlers that write nothing produce an implicit http.StatusOK.
+func (bw *bufferWriter) statusCode() int {
+	if bw.status == 0 {
+		return http.StatusOK
+	}
+	return bw.status
+}
@@ -0,0 +1,90 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type testSink struct {
+	lock sync.Mutex
+	recs []mux.Recording
+}
+
+func (s *testSink) Record(rec mux.Recording) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.recs = append(s.recs, rec)
+}
+
+func TestMux_Record_success(t *testing.T) {
+	sink := &testSink{}
+	m := &mux.Mux{}
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "echo %s", b)
+	})
+	if err := m.Handle(http.MethodPost, "https://localhost/echo", echo, mux.Record(&mux.Recorder{Sink: sink, MaxBodySize: 10})); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "https://localhost/echo?x=1", strings.NewReader("gopher"))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Test", "yes")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+
+	if want, got := 1, len(sink.recs); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+	rec := sink.recs[0]
+	if want, got := "POST https://localhost/echo?x=1 gopher 201 echo gophe", fmt.Sprintf("%s %s %s %d %s", rec.Method, rec.URL, rec.Body, rec.Status, rec.ResponseBody); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "POST+https://localhost/echo", rec.Route.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "||yes", rec.Header.Get("Authorization")+"|"+rec.ResponseHeader.Get("Set-Cookie")+"|"+rec.Header.Get("X-Test"); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	//Replaying the (truncated) recording against the same handler produces a different body.
+	results := m.Replay(rec)
+	if want, got := "201 echo gopher false", fmt.Sprintf("%d %s %t", results[0].Status, results[0].Body, results[0].Matches()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	//Replaying a complete recording matches.
+	rec.ResponseBody = []byte("echo gopher")
+	if !m.Replay(rec)[0].Matches() {
+		t.Fatal("expected: matches")
+	}
+}
+
+func TestMux_Handle_failRecorderMustHaveSink(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Record(&mux.Recorder{})); err != mux.ErrRecorderMustHaveSink {
+		t.Fatal("expected: mux.ErrRecorderMustHaveSink")
+	}
+}
@@ -0,0 +1,112 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+//Errors returned by the Recover option.
+var (
+	//ErrRecoveryMustBeValid is returned by Handle method when the Recover option receives a nil Recovery or a Status other than 500 or 503.
+	ErrRecoveryMustBeValid = errors.New("mux: invalid recovery policy")
+)
+
+//Recovery is the panic policy of a route, set by the Recover option. Public and internal routes usually need different exposure.
+type Recovery struct {
+	//Status is the response status of recovered panics: http.StatusInternalServerError or http.StatusServiceUnavailable. If zero, 500 is used.
+	Status int
+	//Logger receives the recovered panics. If nil, the panics are not logged.
+	Logger *log.Logger
+	//Stack includes the stack trace of the panic in the log and in the recording.
+	Stack bool
+	//Sink receives a Recording of the request for each recovered panic (Eg: a RingSink served in a debug route), with the panic (and the stack trace) in ResponseBody.
+	//Authorization and cookie headers are redacted. If nil, no recording is made.
+	Sink RecordSink
+}
+
+//Recover makes the route recover the panics of its handler, answering with the rc.Status rendered by the Mux ErrorRenderer (See `mux.Mux.ErrorRenderer`).
+//
+//Panics with `http.ErrAbortHandler` are not recovered, as they are used to abort responses. If the handler already started the response, nothing else is written.
+//Recovered panics are reported by Stats as server errors, not as panics.
+//
+//Errors
+//
+//• mux.ErrRecoveryMustBeValid
+func Recover(rc *Recovery) RouteOption {
+	return func(o *routeOptions) error {
+		if rc == nil || (rc.Status != 0 && rc.Status != http.StatusInternalServerError && rc.Status != http.StatusServiceUnavailable) {
+			return ErrRecoveryMustBeValid
+		}
+		o.recovery = rc
+		return nil
+	}
+}
+
+//wrap creates a handler that recovers the panics of the next one.
+func (rc *Recovery) wrap(next http.Handler, route RouteInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		start := time.Now()
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			status := rc.Status
+			if status == 0 {
+				status = http.StatusInternalServerError
+			}
+			rc.report(r, route, p, status, start)
+			if rw.status != 0 {
+				return
+			}
+			renderError(rw, r, status, "")
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+//report logs and records a recovered panic.
+func (rc *Recovery) report(r *http.Request, route RouteInfo, p interface{}, status int, start time.Time) {
+	msg := fmt.Sprintf("panic: %v", p)
+	if rc.Stack {
+		msg += "\n" + string(debug.Stack())
+	}
+	if rc.Logger != nil {
+		rc.Logger.Printf("mux: %s %s: %s", route, requestURL(r), msg)
+	}
+	if rc.Sink != nil {
+		rc.Sink.Record(Recording{
+			Time:         start,
+			Route:        route,
+			Method:       r.Method,
+			URL:          requestURL(r),
+			Header:       (&Recorder{}).redact(r.Header),
+			Status:       status,
+			ResponseBody: []byte(msg),
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Recover_success(t *testing.T) {
+	m := &mux.Mux{ProblemDetails: true}
+	logs := &bytes.Buffer{}
+	ring := &mux.RingSink{}
+	boom := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("partial") != "" {
+			w.Write([]byte("partial"))
+		}
+		panic("boom")
+	})
+	public := m.Group(mux.Recover(&mux.Recovery{Status: http.StatusServiceUnavailable}))
+	internal := m.Group(mux.Recover(&mux.Recovery{Logger: log.New(logs, "", 0), Stack: true, Sink: ring}))
+	if err := public.Handle(http.MethodGet, "http://localhost/public", boom); err != nil {
+		t.Fatal(err)
+	}
+	if err := internal.Handle(http.MethodGet, "http://localhost/internal", boom); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url, want string
+	}{
+		{"http://localhost/public", "503 application/problem+json"},
+		{"http://localhost/internal", "500 application/problem+json"},
+		{"http://localhost/internal?partial=1", "200 text/plain; charset=utf-8"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.url, nil)
+		req.Header.Set("Authorization", "secret")
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Content-Type")); test.want != got {
+			t.Fatalf("%s: want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+
+	if want, got := "mux: GET+http://localhost/internal http://localhost/internal: panic: boom\ngoroutine", logs.String(); !strings.HasPrefix(got, want) {
+		t.Fatalf("want prefix=%q, got=%q", want, got)
+	}
+	recs := ring.Recordings()
+	if want, got := "2 500 true", fmt.Sprint(len(recs), " ", recs[0].Status, " ", recs[0].Header.Get("Authorization") == ""); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Recover_successAbortHandler(t *testing.T) {
+	m := &mux.Mux{}
+	abort := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/", abort, mux.Recover(&mux.Recovery{})); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Fatalf("want=%v, got=%v", http.ErrAbortHandler, p)
+		}
+	}()
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+}
+
+func TestMux_Handle_failRecoveryMustBeValid(t *testing.T) {
+	m := &mux.Mux{}
+	for _, rc := range []*mux.Recovery{nil, {Status: http.StatusBadGateway}} {
+		if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Recover(rc)); err != mux.ErrRecoveryMustBeValid {
+			t.Fatal("expected: mux.ErrRecoveryMustBeValid")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+)
+
+//ErrorRenderer writes the error responses generated by a Mux: not found (404), method not allowed (405), semicolon rejection (400), body constraints (400, 411, 413, 415), cross-origin rejection (403),
+//throttling (429, 503), fault injection, recovered panics (500, 503) and the internal errors (500) of the handlers served by the Mux, like ServeStats and ServeOpenAPI.
+//
+//The status and the headers of the response (Eg: Allow, Retry-After) are already decided when RenderError is called. The renderer must write the Problem.Status.
+type ErrorRenderer interface {
+	RenderError(w http.ResponseWriter, r *http.Request, p Problem)
+}
+
+//TextErrors renders errors as plain text, like http.Error and http.NotFound. It is the default ErrorRenderer.
+type TextErrors struct{}
+
+//RenderError implements ErrorRenderer.
+func (TextErrors) RenderError(w http.ResponseWriter, r *http.Request, p Problem) {
+	if p.Status == http.StatusNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	http.Error(w, p.Title, p.Status)
+}
+
+//ProblemErrors renders errors as RFC 7807 application/problem+json documents. It is used when Mux.ProblemDetails is set.
+type ProblemErrors struct{}
+
+//RenderError implements ErrorRenderer.
+func (ProblemErrors) RenderError(w http.ResponseWriter, r *http.Request, p Problem) {
+	doc, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	w.Write(doc)
+}
+
+//HTMLErrors renders errors as minimal HTML pages, showing the title and the detail of the problem.
+type HTMLErrors struct{}
+
+//RenderError implements ErrorRenderer.
+func (HTMLErrors) RenderError(w http.ResponseWriter, r *http.Request, p Problem) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	title := html.EscapeString(fmt.Sprintf("%d %s", p.Status, p.Title))
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html><head><title>%s</title></head><body><h1>%s</h1>", title, title)
+	if p.Detail != "" {
+		fmt.Fprintf(w, "<p>%s</p>", html.EscapeString(p.Detail))
+	}
+	fmt.Fprint(w, "</body></html>\n")
+}
+
+//errorRenderer returns the renderer of the errors generated by the Mux.
+func (m *Mux) errorRenderer() ErrorRenderer {
+	if m.ErrorRenderer != nil {
+		return m.ErrorRenderer
+	}
+	if m.ProblemDetails {
+		return ProblemErrors{}
+	}
+	return TextErrors{}
+}
+
+//renderError writes an error generated inside a route handler chain, using the renderer of the Mux dispatching the request.
+//Outside a Mux dispatch, TextErrors is used.
+func renderError(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	var renderer ErrorRenderer = TextErrors{}
+	if m, err := Get(r); err == nil {
+		renderer = m.errorRenderer()
+	}
+	renderer.RenderError(w, r, newProblem(r, status, detail))
+}
@@ -0,0 +1,73 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type statusRenderer struct{}
+
+func (statusRenderer) RenderError(w http.ResponseWriter, r *http.Request, p mux.Problem) {
+	w.WriteHeader(p.Status)
+	fmt.Fprintf(w, "rendered %d %s", p.Status, p.Instance)
+}
+
+func TestMux_ErrorRenderer_success(t *testing.T) {
+	m := &mux.Mux{ErrorRenderer: statusRenderer{}, SemicolonPolicy: mux.SemicolonReject}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	fi := &mux.FaultInjector{ErrorRate: 1, ErrorStatus: http.StatusBadGateway}
+	fi.Enable()
+	if err := m.Handle(http.MethodGet, "http://localhost/faulty", http.HandlerFunc(emptyHandler), mux.Faults(fi)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, url, want string
+	}{
+		{http.MethodGet, "http://localhost/users", "404 rendered 404 /users"},
+		{http.MethodPost, "http://localhost/orders", "405 GET rendered 405 /orders"},
+		{http.MethodGet, "http://localhost/orders?a=1;b=2", "400 rendered 400 /orders"},
+		{http.MethodGet, "http://localhost/faulty", "502 rendered 502 /faulty"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(test.method, test.url, nil))
+		got := fmt.Sprint(rr.Code, " ", rr.Body.String())
+		if allow := rr.Header().Get("Allow"); allow != "" {
+			got = fmt.Sprint(rr.Code, " ", allow, " ", rr.Body.String())
+		}
+		if test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestHTMLErrors_RenderError_success(t *testing.T) {
+	m := &mux.Mux{ErrorRenderer: mux.HTMLErrors{}}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/<users>", nil))
+	want := "404 text/html; charset=utf-8 <!DOCTYPE html>\n<html><head><title>404 Not Found</title></head><body><h1>404 Not Found</h1><p>No route matches the request.</p></body></html>\n"
+	if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Content-Type"), " ", rr.Body.String()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
@@ -0,0 +1,104 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	//Used in request contexts.
+	ctxRequestIDValue = "gitlab.com/gopherburrow/mux RequestID"
+	//defaultRequestIDHeader is the header used when RequestIDs.Header is empty.
+	defaultRequestIDHeader = "X-Request-Id"
+	//maxRequestIDLength limits the size of trusted incoming request ids.
+	maxRequestIDLength = 128
+)
+
+//The key used to store the request id in ServeHTTP.
+var ctxRequestID = ctxType(ctxRequestIDValue)
+
+//RequestIDs configures the request id assignment, set in Mux.RequestIDs field.
+//
+//Each request served by the Mux receives an id, exposed to handlers by RequestID function, echoed in the response header,
+//reported to the Observer in Observation.RequestID and included in the error responses generated by the Mux (See `mux.Problem`).
+type RequestIDs struct {
+	//Header is the request and response header carrying the id. If empty, "X-Request-Id" is used.
+	Header string
+	//Trust honors the id of the incoming request header, when it is present and valid (up to 128 printable ASCII characters).
+	//It must be set only behind a proxy that assigns or sanitizes the header.
+	Trust bool
+	//Generate creates new ids. If nil, 16 random bytes encoded as hex are used.
+	Generate func() string
+}
+
+//header returns the header carrying the id.
+func (ri *RequestIDs) header() string {
+	if ri.Header == "" {
+		return defaultRequestIDHeader
+	}
+	return ri.Header
+}
+
+//assign stores the request id in the request context and in the response header.
+func (ri *RequestIDs) assign(w http.ResponseWriter, r *http.Request) *http.Request {
+	header := ri.header()
+	id := ""
+	if ri.Trust {
+		id = r.Header.Get(header)
+		if !validRequestID(id) {
+			id = ""
+		}
+	}
+	if id == "" && ri.Generate != nil {
+		id = ri.Generate()
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	w.Header().Set(header, id)
+	return r.WithContext(context.WithValue(r.Context(), ctxRequestID, id))
+}
+
+//validRequestID tests if an incoming id can be trusted, so it cannot inject content into logs or headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+//newRequestID generates a random request id.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+//RequestID retrieves the id assigned to the request by a Mux with RequestIDs set. It returns "" if no id was assigned.
+func RequestID(r *http.Request) string {
+	id, _ := r.Context().Value(ctxRequestID).(string)
+	return id
+}
@@ -0,0 +1,68 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_RequestIDs_success(t *testing.T) {
+	m := &mux.Mux{RequestIDs: &mux.RequestIDs{Header: "X-Trace", Trust: true, Generate: func() string { return "gen" }}, ProblemDetails: true}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mux.RequestID(r))
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, incoming, want string
+	}{
+		{"/orders", "", "gen gen"},
+		{"/orders", "abc-123", "abc-123 abc-123"},
+		{"/orders", "bad id\n", "gen gen"},
+		{"/users", "abc-123", `abc-123 {"type":"about:blank","title":"Not Found","status":404,"detail":"No route matches the request.","instance":"/users","requestId":"abc-123"}`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil)
+		if test.incoming != "" {
+			req.Header.Set("X-Trace", test.incoming)
+		}
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Header().Get("X-Trace"), " ", rr.Body.String()); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestMux_RequestIDs_successGenerated(t *testing.T) {
+	m := &mux.Mux{RequestIDs: &mux.RequestIDs{}}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/orders", nil)
+	req.Header.Set("X-Request-Id", "untrusted")
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if id := rr.Header().Get("X-Request-Id"); len(id) != 32 {
+		t.Fatalf("want a 32 hex digits id, got=%q", id)
+	}
+}
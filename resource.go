@@ -0,0 +1,78 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+//ResourceHandlers holds the handlers of the standard REST routes of a resource. Nil handlers are not registered.
+type ResourceHandlers struct {
+	//Index lists the resource items: GET collection.
+	Index http.Handler
+	//Create adds an item: POST collection.
+	Create http.Handler
+	//Show retrieves an item: GET collection/{id}.
+	Show http.Handler
+	//Update replaces an item: PUT collection/{id}.
+	Update http.Handler
+	//Delete removes an item: DELETE collection/{id}.
+	Delete http.Handler
+}
+
+//Resource registers the standard REST routes of a collection in one call. Eg: m.Resource("https://api.example.com/widgets", mux.ResourceHandlers{...}).
+//
+//Item routes append the {id} path variable to the collection path, so the item id can be extracted using PathVars method.
+//Query routing in the collectionPattern and the opts functions are applied to every route.
+//
+//The registration is atomic: if any route is invalid or conflicts, no route is registered.
+//
+//Errors
+//
+//• Any error returned by Handle method.
+func (m *Mux) Resource(collectionPattern string, handlers ResourceHandlers, opts ...RouteOption) error {
+	//Split the query routing, so the item path can be appended.
+	parts := strings.SplitN(collectionPattern, "?", 2)
+	itemPattern := strings.TrimSuffix(parts[0], "/") + "/{id}"
+	if len(parts) == 2 {
+		itemPattern += "?" + parts[1]
+	}
+
+	routes := []struct {
+		method     string
+		urlPattern string
+		handler    http.Handler
+	}{
+		{http.MethodGet, collectionPattern, handlers.Index},
+		{http.MethodPost, collectionPattern, handlers.Create},
+		{http.MethodGet, itemPattern, handlers.Show},
+		{http.MethodPut, itemPattern, handlers.Update},
+		{http.MethodDelete, itemPattern, handlers.Delete},
+	}
+	entries := make([]muxEntry, 0, len(routes))
+	for _, r := range routes {
+		if r.handler == nil {
+			continue
+		}
+		e, err := newHandleEntry(r.method, r.urlPattern, r.handler, opts)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+	}
+	return m.insertAll(entries)
+}
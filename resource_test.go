@@ -0,0 +1,66 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Resource_success(t *testing.T) {
+	m := &mux.Mux{}
+	show := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, _ := mux.Get(r)
+		fmt.Fprint(w, "show ", m.PathVars(r)["id"])
+	})
+	if err := m.Resource("http://localhost/widgets?v=2", mux.ResourceHandlers{
+		Index:  newTestHandler("index"),
+		Create: newTestHandler("create"),
+		Show:   show,
+		Delete: newTestHandler("delete"),
+	}, mux.Owner("widgets")); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "[GET+http://localhost/widgets?v=2 POST+http://localhost/widgets?v=2 DELETE+http://localhost/widgets/{id}?v=2 GET+http://localhost/widgets/{id}?v=2]", fmt.Sprint(m.Routes()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/widgets/42?v=2", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "show 42", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Resource_failRouteMustNotConflict(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodDelete, "http://localhost/widgets/{name}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	h := http.HandlerFunc(emptyHandler)
+	if err := m.Resource("http://localhost/widgets", mux.ResourceHandlers{Index: h, Create: h, Show: h, Update: h, Delete: h}); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if want, got := 1, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
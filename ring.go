@@ -0,0 +1,82 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+//defaultRingSize is the number of recordings kept when RingSink.Size is zero.
+const defaultRingSize = 100
+
+//RingSink is an in-memory RecordSink keeping only the last recordings, so a route can be debugged in production without unbounded memory.
+//Eg: m.Handle("POST", "https://api.example.com/orders", h, mux.Record(&mux.Recorder{Sink: ring})).
+//
+//RingSink is also a debug http.Handler, serving the kept recordings (oldest first) as a JSON document. Eg: m.Handle("GET", "https://admin.example.com/debug/orders", ring).
+//As recordings may still contain sensitive data not covered by Recorder.RedactHeaders, the debug route must be protected.
+type RingSink struct {
+	//Size is the number of recordings kept. If zero, 100 recordings are kept.
+	Size int
+	lock sync.Mutex
+	recs []Recording
+	next int
+}
+
+//Record implements RecordSink, overwriting the oldest recording when the ring is full.
+func (rs *RingSink) Record(rec Recording) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	size := rs.Size
+	if size <= 0 {
+		size = defaultRingSize
+	}
+	if len(rs.recs) < size {
+		rs.recs = append(rs.recs, rec)
+		return
+	}
+	rs.recs[rs.next%len(rs.recs)] = rec
+	rs.next = (rs.next + 1) % len(rs.recs)
+}
+
+//Recordings returns a copy of the kept recordings, oldest first.
+func (rs *RingSink) Recordings() []Recording {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	recs := make([]Recording, 0, len(rs.recs))
+	recs = append(recs, rs.recs[rs.next:]...)
+	return append(recs, rs.recs[:rs.next]...)
+}
+
+//Reset discards the kept recordings.
+func (rs *RingSink) Reset() {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	rs.recs, rs.next = nil, 0
+}
+
+//ServeHTTP serves the kept recordings as a JSON document.
+func (rs *RingSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	doc, err := json.Marshal(rs.Recordings())
+	if err != nil {
+		renderError(w, r, http.StatusInternalServerError, "")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(doc)
+}
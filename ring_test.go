@@ -0,0 +1,66 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestRingSink_success(t *testing.T) {
+	m := &mux.Mux{}
+	ring := &mux.RingSink{Size: 2}
+	read := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+	})
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", read, mux.Record(&mux.Recorder{Sink: ring})); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/debug/orders", ring); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{"a", "b", "c"} {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/orders", strings.NewReader(body))
+		req.Header.Set("Authorization", "secret")
+		m.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/debug/orders", nil))
+	var recs []mux.Recording
+	if err := json.Unmarshal(rr.Body.Bytes(), &recs); err != nil {
+		t.Fatal(err)
+	}
+	got := ""
+	for _, rec := range recs {
+		got += fmt.Sprint(rec.Method, " ", rec.Route.URLPattern, " ", string(rec.Body), " ", rec.Header.Get("Authorization"), ";")
+	}
+	if want := "POST http://localhost/orders b ;POST http://localhost/orders c ;"; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	ring.Reset()
+	if want, got := 0, len(ring.Recordings()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
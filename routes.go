@@ -0,0 +1,257 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//RouteInfo describes a registered route.
+type RouteInfo struct {
+	//Method is the HTTP method of the route. Eg: GET.
+	Method string
+	//URLPattern is the canonical URL pattern of the route. Eg: https://localhost:8080/path/{var}?query=value
+	URLPattern string
+	//Owner is the module or team name set by mux.Owner option.
+	Owner string
+	//TraceSampleRate is the fraction of requests that should be traced, set by mux.TraceSampleRate option.
+	TraceSampleRate float64
+	//Handler is a best-effort description of the route handler: its Describe method (See `mux.Describer`), its function name or its type name.
+	Handler string
+	//Summary and Description document the route, set by mux.Annotate option.
+	Summary     string
+	Description string
+	//RequestType and ResponseType are the body types of the route, set by mux.Types option.
+	RequestType  TypeRef
+	ResponseType TypeRef
+	//ProtoMajor is the HTTP major version required by the route, set by mux.ProtoMajor option. Zero means any version.
+	ProtoMajor int
+	//Countries and ExceptCountries are the country codes allowed and denied by the route, set by mux.Countries and mux.ExceptCountries options.
+	Countries       []string
+	ExceptCountries []string
+	//UserAgent is the User-Agent pattern of the route, set by mux.UserAgent option.
+	UserAgent string
+	//Flag is the feature flag key of the route, set by mux.Flag option.
+	Flag string
+	//ContentTypes are the request media types accepted by the route, set by mux.ContentTypes option.
+	ContentTypes []string
+	//Protections are the mechanisms protecting the route against forged requests, set by mux.Protected option.
+	Protections []string
+	//CORSOrigins are the origins allowed to make cross-origin requests to the route, set by mux.CORS option.
+	CORSOrigins []string
+	//CORSHeaders are the request headers allowed in cross-origin requests to the route, set by mux.CORSHeaders option.
+	CORSHeaders []string
+	//Deprecation is set by mux.Deprecated option. Nil means the route is not deprecated.
+	Deprecation *Deprecation
+	//ContextTimeout is the request context time budget, set by mux.ContextTimeout option. Zero means no budget.
+	ContextTimeout time.Duration
+	//Streaming tells if the route is long-lived, set by mux.Streaming option.
+	Streaming bool
+	//GatewayTimeouts are the default timeouts of the Proxy backends of the route, set by mux.Gateway option.
+	GatewayTimeouts GatewayTimeouts
+	//Exempt tells if the route is out of the Mux-wide wrappers, set by mux.Exempt option.
+	Exempt bool
+	//ShutdownExempt and Readiness tell how the route behaves while the Mux is draining, set by mux.ShutdownExempt and mux.Readiness options.
+	ShutdownExempt bool
+	Readiness      bool
+	//Quarantined tells if the route was quarantined by Quarantine method, and QuarantineNote is the note answered with http.StatusGone.
+	Quarantined    bool
+	QuarantineNote string
+	//Hits is the number of requests dispatched to the route, counted when Mux.AuditHits is set.
+	Hits uint64
+	//query is the query routing of the route.
+	query queryRoute
+}
+
+//newRouteInfo describes a routing table entry.
+func newRouteInfo(e muxEntry) RouteInfo {
+	ri := RouteInfo{
+		Method:          e.route.method,
+		URLPattern:      e.route.urlPattern(),
+		Owner:           e.options.owner,
+		Handler:         describeHandler(e.handler),
+		TraceSampleRate: 1,
+		Summary:         e.options.summary,
+		Description:     e.options.description,
+		RequestType:     e.options.requestType,
+		ResponseType:    e.options.responseType,
+		ProtoMajor:      e.route.protoMajor,
+		Countries:       e.route.countries,
+		ExceptCountries: e.route.exceptCountries,
+		UserAgent:       e.route.userAgent.String(),
+		Flag:            e.route.flag,
+		ContentTypes:    e.route.contentTypes,
+		Protections:     e.options.protections,
+		Deprecation:     e.options.deprecation,
+		CORSOrigins:     e.options.corsOrigins,
+		CORSHeaders:     e.options.corsHeaders,
+		ContextTimeout:  e.options.contextTimeout,
+		Streaming:       e.options.streaming,
+		GatewayTimeouts: e.options.gatewayTimeouts,
+		Exempt:          e.options.exempt,
+		ShutdownExempt:  e.options.shutdownExempt,
+		Readiness:       e.options.readiness,
+		Hits:            atomic.LoadUint64(&e.stats.hits),
+		query:           e.route.query,
+	}
+	if e.options.hasTraceSampleRate {
+		ri.TraceSampleRate = e.options.traceSampleRate
+	}
+	if e.quarantine != nil {
+		ri.Quarantined, ri.QuarantineNote = true, *e.quarantine
+	}
+	return ri
+}
+
+//QueryConstraint describes a query routing test of a route.
+type QueryConstraint struct {
+	//Name is the query parameter name.
+	Name string
+	//Value is the tested value or value constraint (Eg: {:1-100}). It is empty for presence and absence tests.
+	Value string
+	//Absent tells the parameter must be missing. Eg: ?!debug
+	Absent bool
+}
+
+//QueryConstraints returns the query routing tests of the route, sorted by name and value.
+//
+//So generic middleware (Eg: computing cache keys) can know which query parameters are routing relevant.
+func (ri RouteInfo) QueryConstraints() []QueryConstraint {
+	constraints := make([]QueryConstraint, len(ri.query))
+	for i, e := range ri.query {
+		constraints[i] = QueryConstraint{Name: e.Name, Value: e.Value, Absent: e.absent}
+	}
+	return constraints
+}
+
+//CurrentRoute retrieves the description of the route that matched a request dispatched by a Mux.
+//
+//Possible error returns:
+//
+//• mux.ErrRequestMustHaveContext
+func CurrentRoute(r *http.Request) (RouteInfo, error) {
+	s, ok := stateOf(r)
+	if !ok {
+		return RouteInfo{}, ErrRequestMustHaveContext
+	}
+	return newRouteInfo(s.entry), nil
+}
+
+//String is Stringer Interface for RouteInfo. Format: method+scheme://host:port/path/...?query1=value&...
+func (ri RouteInfo) String() string {
+	return ri.Method + "+" + ri.URLPattern
+}
+
+//Routes returns the description of all registered routes, in routing table order.
+func (m *Mux) Routes() []RouteInfo {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	return newRouteInfos(m.entries)
+}
+
+//newRouteInfos describes a set of routing table entries.
+func newRouteInfos(entries muxEntries) []RouteInfo {
+	routes := make([]RouteInfo, len(entries))
+	for i, e := range entries {
+		routes[i] = newRouteInfo(e)
+	}
+	return routes
+}
+
+//ExampleRequests generates a representative request for each registered route, in routing table order.
+//
+//Path variables are filled with their names as placeholders ({*} is filled with "example"), and every query parameter tested by the route is included.
+//Wildcard hosts are filled with an "example" subdomain, and wildcard ports are omitted.
+//Query value constraints are filled with a value they accept, when it can be determined (Eg: the minimum of a numeric range).
+//The requests can be passed to ServeHTTP, so smoke tests can verify that every registered route responds.
+func (m *Mux) ExampleRequests() []*http.Request {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	reqs := make([]*http.Request, 0, len(m.entries))
+	for _, e := range m.entries {
+		reqs = append(reqs, newExampleRequest(e.route))
+	}
+	return reqs
+}
+
+//newExampleRequest creates a request that matches the route.
+func newExampleRequest(route *muxRoute) *http.Request {
+	b := bytes.Buffer{}
+	//Routes without a scheme are exemplified with http.
+	scheme := route.scheme
+	if scheme == anyScheme {
+		scheme = "http"
+	}
+	b.WriteString(scheme)
+	b.WriteString("://")
+	b.WriteString(exampleHost(route.host))
+	b.WriteString("/")
+	for i, seg := range route.path {
+		if i > 0 {
+			b.WriteString("/")
+		}
+		name, kind, isVar := parsePathVar(seg)
+		if !isVar {
+			b.WriteString(seg)
+			continue
+		}
+		if name == "*" {
+			name = "example"
+		}
+		if t, typed := pathVarTypes[kind]; typed {
+			name = t.example
+		}
+		b.WriteString(url.PathEscape(name))
+	}
+	sep := "?"
+	for _, q := range route.query {
+		//Absence tests are satisfied by leaving the parameter out.
+		if q.absent {
+			continue
+		}
+		b.WriteString(sep)
+		sep = "&"
+		b.WriteString(url.QueryEscape(q.Name))
+		if q.pattern() == "" {
+			continue
+		}
+		value := q.Value
+		if q.capture != "" {
+			value = q.capture
+		}
+		if q.constraint != nil {
+			value = q.constraint.example(q.Name)
+		}
+		b.WriteString("=")
+		b.WriteString(url.QueryEscape(value))
+	}
+
+	//Routes were validated at registration, so the URL is always valid.
+	req, _ := http.NewRequest(route.method, b.String(), nil)
+	if route.scheme == "https" {
+		req.TLS = &tls.ConnectionState{}
+	}
+	if len(route.contentTypes) > 0 {
+		req.Header.Set("Content-Type", strings.Replace(route.contentTypes[0], "/*", "/example", 1))
+	}
+	return req
+}
@@ -0,0 +1,158 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Routes_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "https://localhost:8080/orders/{id}", http.HandlerFunc(emptyHandler), mux.Owner("orders")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "https://localhost:8080/billing?invoice", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := m.Routes()
+	if want, got := "[GET+https://localhost:8080/billing?invoice GET+https://localhost:8080/orders/{id}]", fmt.Sprint(routes); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "", routes[0].Owner; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "orders", routes[1].Owner; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Handle_failConflictAttribution(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "https://localhost:8080/orders/{id}", http.HandlerFunc(emptyHandler), mux.Owner("orders")); err != nil {
+		t.Fatal(err)
+	}
+	err := m.Handle(http.MethodGet, "https://localhost:8080/orders/export", http.HandlerFunc(emptyHandler), mux.Owner("reports"))
+	if !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	var conflict *mux.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatal("expected: *mux.ConflictError")
+	}
+	if want, got := "reports", conflict.Owner; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "orders", conflict.ExistingOwner; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := `mux: route conflicting with a pre existing route: GET+https://localhost:8080/orders/export (owner "reports") conflicts with GET+https://localhost:8080/orders/{id} (owner "orders")`, err.Error(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_ExampleRequests_success(t *testing.T) {
+	m := &mux.Mux{}
+	patterns := []string{
+		"https://localhost:8080/orders/{id}/items/{*}",
+		"http://localhost/billing?invoice&format=pdf&format=a+b",
+		"http://localhost/",
+	}
+	for _, p := range patterns {
+		if err := m.Handle(http.MethodGet, p, newTestHandler(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reqs := m.ExampleRequests()
+	urls := []string{}
+	for _, req := range reqs {
+		urls = append(urls, req.Method+" "+req.URL.String())
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := http.StatusOK, rr.Code; want != got {
+			t.Fatalf("url=%q, want=%d, got=%d", req.URL, want, got)
+		}
+	}
+	if want, got := "[GET http://localhost/ GET http://localhost/billing?format=a+b&format=pdf&invoice GET https://localhost:8080/orders/id/items/example]", fmt.Sprint(urls); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestCurrentRoute_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, err := mux.CurrentRoute(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(w, route, " ", route.QueryConstraints())
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/orders?page={:1-100}&api-key", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/orders?api-key=x&page=2&utm=y", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "GET+http://localhost/orders?api-key&page={:1-100} [{api-key  false} {page {:1-100} false}]", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestCurrentRoute_failMustHaveContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	if _, err := mux.CurrentRoute(req); err != mux.ErrRequestMustHaveContext {
+		t.Fatal("expected: mux.ErrRequestMustHaveContext")
+	}
+}
+
+type describedHandler struct{}
+
+func (describedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (describedHandler) Describe() string {
+	return "orders.Create (v2)"
+}
+
+func TestRouteInfo_Handler_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/a", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/b", newTestHandler("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", describedHandler{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ""
+	for _, ri := range m.Routes() {
+		got += ri.Handler + ";"
+	}
+	if want := "gitlab.com/gopherburrow/mux_test.emptyHandler;*mux_test.testHandler;orders.Create (v2);"; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if want, got := "GET+http://localhost/a -> gitlab.com/gopherburrow/mux_test.emptyHandler\nGET+http://localhost/b -> *mux_test.testHandler\nPOST+http://localhost/orders -> orders.Create (v2)\n", m.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
@@ -0,0 +1,194 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+//TypeRef names a Go type exchanged by a route, set by mux.Types option, so client SDK generators can reuse or mirror it.
+type TypeRef struct {
+	//Name is the Go type name, qualified by its package path. Eg: *gitlab.com/acme/orders.Order or []gitlab.com/acme/orders.Item .
+	Name string `json:"name"`
+	//Description documents the value. Eg: The order to be created.
+	Description string `json:"description,omitempty"`
+}
+
+//TypeOf names the Go type of v with a description, qualifying named types by their package path. Eg: mux.TypeOf(orders.Order{}, "The created order.").
+func TypeOf(v interface{}, description string) TypeRef {
+	if v == nil {
+		return TypeRef{Description: description}
+	}
+	return TypeRef{Name: goTypeName(reflect.TypeOf(v)), Description: description}
+}
+
+//goTypeName names a Go type, qualifying named types by their package path.
+func goTypeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + goTypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + goTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + goTypeName(t.Key()) + "]" + goTypeName(t.Elem())
+	}
+	return t.String()
+}
+
+//Types documents the request and response body types of a route, reported by RouteInfo and the ClientSpec. A TypeRef without Name means no body.
+func Types(request, response TypeRef) RouteOption {
+	return func(o *routeOptions) error {
+		o.requestType, o.responseType = request, response
+		return nil
+	}
+}
+
+//ClientSpec is a machine-readable description of the routes of a Mux, sufficient for client SDK generation.
+//It complements the OpenAPI document with the Go types of route bodies and variables.
+type ClientSpec struct {
+	//Name and Version are the Mux APITitle and APIVersion.
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	//Operations are listed in routing table order.
+	Operations []ClientOperation `json:"operations"`
+}
+
+//ClientOperation describes a route in a ClientSpec.
+type ClientOperation struct {
+	Method     string `json:"method"`
+	URLPattern string `json:"urlPattern"`
+	//Summary and Description are set by mux.Annotate option.
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	//Params are the host and path variables, in URL pattern order, followed by the query parameters, sorted by name.
+	Params []ClientParam `json:"params,omitempty"`
+	//Request and Response are set by mux.Types option. Nil means no body.
+	Request  *TypeRef `json:"request,omitempty"`
+	Response *TypeRef `json:"response,omitempty"`
+	//Deprecated is set by mux.Deprecated option.
+	Deprecated bool `json:"deprecated"`
+}
+
+//ClientParam describes a variable or query parameter of a ClientOperation.
+type ClientParam struct {
+	//In is where the parameter is sent: "host", "path" or "query".
+	In   string `json:"in"`
+	Name string `json:"name"`
+	//Type is the Go type of the parameter value. Eg: int64 for {id:int}. Query presence tests (Eg: ?verbose) are bool.
+	Type string `json:"type"`
+	//Values are the values accepted by query value tests, when they are enumerable. Eg: json and xml for ?format={:json|xml} .
+	Values []string `json:"values,omitempty"`
+	//Min and Max are the limits of query numeric ranges. Eg: ?page={:1-100}
+	Min *int64 `json:"min,omitempty"`
+	Max *int64 `json:"max,omitempty"`
+}
+
+//ClientSpec describes the routing table as a ClientSpec.
+func (m *Mux) ClientSpec() ClientSpec {
+	name := m.APITitle
+	if name == "" {
+		name = "API"
+	}
+	routes := m.routesWithInternals()
+	spec := ClientSpec{Name: name, Version: m.APIVersion, Operations: make([]ClientOperation, len(routes))}
+	for i, ri := range routes {
+		op := ClientOperation{
+			Method:      ri.info.Method,
+			URLPattern:  ri.info.URLPattern,
+			Summary:     ri.info.Summary,
+			Description: ri.info.Description,
+			Params:      clientParams(ri.route),
+			Deprecated:  ri.info.Deprecation != nil,
+		}
+		if t := ri.info.RequestType; t.Name != "" {
+			op.Request = &t
+		}
+		if t := ri.info.ResponseType; t.Name != "" {
+			op.Response = &t
+		}
+		spec.Operations[i] = op
+	}
+	return spec
+}
+
+//clientParams describes the variables and query parameters of a route.
+func clientParams(route *muxRoute) []ClientParam {
+	params := []ClientParam{}
+	if route.hostVar != "" {
+		params = append(params, ClientParam{In: "host", Name: route.hostVar, Type: "string"})
+	}
+	for _, seg := range route.path {
+		name, kind, isVar := parsePathVar(seg)
+		if !isVar {
+			continue
+		}
+		goType := "string"
+		if kind != "" {
+			goType = pathVarTypes[kind].goType
+		}
+		params = append(params, ClientParam{In: "path", Name: name, Type: goType})
+	}
+
+	//Merge the value tests of the same query parameter.
+	query := []ClientParam{}
+	for _, q := range route.query {
+		if q.absent {
+			continue
+		}
+		i := 0
+		for ; i < len(query) && query[i].Name != q.Name; i++ {
+		}
+		if i == len(query) {
+			query = append(query, ClientParam{In: "query", Name: q.Name, Type: "string"})
+		}
+		switch c := q.constraint; {
+		case q.Value == "" && q.capture == "":
+			query[i].Type = "bool"
+		case c != nil && c.isRange:
+			min, max := c.min, c.max
+			query[i].Type, query[i].Min, query[i].Max = "int64", &min, &max
+		case c != nil && c.set != nil:
+			query[i].Values = append(query[i].Values, c.set...)
+		case c == nil && q.Value != "":
+			query[i].Values = append(query[i].Values, q.Value)
+		}
+	}
+	sort.SliceStable(query, func(i, j int) bool {
+		return query[i].Name < query[j].Name
+	})
+	return append(params, query...)
+}
+
+//ServeClientSpec registers a GET route serving the JSON ClientSpec generated by ClientSpec method.
+//
+//The spec is generated on each request, so it is kept in sync as routes are added or removed at runtime.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+func (m *Mux) ServeClientSpec(urlPattern string, opts ...RouteOption) error {
+	handler := m.serveJSON(func() ([]byte, error) {
+		return json.Marshal(m.ClientSpec())
+	})
+	return m.Handle(http.MethodGet, urlPattern, handler, opts...)
+}
@@ -0,0 +1,80 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+type testOrder struct {
+	ID int
+}
+
+func TestTypeOf_success(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{testOrder{}, "gitlab.com/gopherburrow/mux_test.testOrder"},
+		{&testOrder{}, "*gitlab.com/gopherburrow/mux_test.testOrder"},
+		{[]testOrder{}, "[]gitlab.com/gopherburrow/mux_test.testOrder"},
+		{map[string]*testOrder{}, "map[string]*gitlab.com/gopherburrow/mux_test.testOrder"},
+		{"", "string"},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if got := mux.TypeOf(test.v, "").Name; test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+	}
+}
+
+func TestMux_ClientSpec_success(t *testing.T) {
+	m := &mux.Mux{APITitle: "Orders", APIVersion: "2.0.0"}
+	if err := m.Handle(http.MethodPost, "http://{tenant}.example.com/users/{user}/orders/{id:int}?format={:json|xml}&verbose&page={:1-100}&!debug", http.HandlerFunc(emptyHandler),
+		mux.Annotate("Create order", ""), mux.Types(mux.TypeOf(testOrder{}, "The new order."), mux.TypeOf(&testOrder{}, ""))); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ServeClientSpec("http://localhost/sdk.json"); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/sdk.json", nil))
+	spec := mux.ClientSpec{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	op := spec.Operations[0]
+	got, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"method":"POST","urlPattern":"http://{tenant}.example.com/users/{user}/orders/{id:int}?!debug\u0026format={:json|xml}\u0026page={:1-100}\u0026verbose","summary":"Create order",` +
+		`"params":[{"in":"host","name":"tenant","type":"string"},{"in":"path","name":"user","type":"string"},{"in":"path","name":"id","type":"int64"},` +
+		`{"in":"query","name":"format","type":"string","values":["json","xml"]},{"in":"query","name":"page","type":"int64","min":1,"max":100},{"in":"query","name":"verbose","type":"bool"}],` +
+		`"request":{"name":"gitlab.com/gopherburrow/mux_test.testOrder","description":"The new order."},"response":{"name":"*gitlab.com/gopherburrow/mux_test.testOrder"},"deprecated":false}`
+	if want != string(got) {
+		t.Fatalf("want=%s,\ngot=%s", want, got)
+	}
+	if want, got := "Orders 2.0.0 2 <nil>", fmt.Sprint(spec.Name, " ", spec.Version, " ", len(spec.Operations), " ", spec.Operations[1].Request); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
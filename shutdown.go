@@ -0,0 +1,85 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+//shutdownPollInterval is how often Shutdown method checks if the in-flight requests are done.
+const shutdownPollInterval = 10 * time.Millisecond
+
+//ShutdownExempt keeps a route serving while the Mux is draining (See Shutdown method). Eg: liveness checks and metrics.
+//Its requests are not waited for by Shutdown method, so long-lived ones (Eg: event streams) do not delay it.
+func ShutdownExempt() RouteOption {
+	return func(o *routeOptions) error {
+		o.shutdownExempt = true
+		return nil
+	}
+}
+
+//Readiness flags a health check route reporting if the instance is ready to receive traffic.
+//While the Mux is draining (See Shutdown method), its requests are answered with http.StatusServiceUnavailable, without calling the handler, so load balancers stop sending traffic.
+func Readiness() RouteOption {
+	return func(o *routeOptions) error {
+		o.readiness = true
+		return nil
+	}
+}
+
+//Shutdown flips the Mux into a draining state, coordinating with load balancers during deploys, and waits for the in-flight requests to be done.
+//
+//While draining, Readiness routes report not-ready and the requests to the other routes are answered with http.StatusServiceUnavailable,
+//a Retry-After header (See Mux.ShutdownRetryAfter) and a "Connection: close" header. ShutdownExempt routes keep serving.
+//
+//Only the requests dispatched to the handlers of routes not ShutdownExempt are tracked. It does not close listeners or connections: call `http.Server.Shutdown` afterwards.
+//If the context expires before the in-flight requests are done, the context error is returned. The Mux keeps draining.
+func (m *Mux) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&m.draining, 1)
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for atomic.LoadInt32(&m.inFlight) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+//drain answers a request dispatched while the Mux is draining, telling if the route handler must not be called.
+func (m *Mux) drain(w http.ResponseWriter, r *http.Request, e muxEntry) bool {
+	if atomic.LoadInt32(&m.draining) == 0 || e.options.shutdownExempt {
+		return false
+	}
+	if e.options.readiness {
+		renderError(w, r, http.StatusServiceUnavailable, "The server is shutting down.")
+		return true
+	}
+	retryAfter := m.ShutdownRetryAfter
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(int64((retryAfter+time.Second-1)/time.Second), 10))
+	w.Header().Set("Connection", "close")
+	renderError(w, r, http.StatusServiceUnavailable, "The server is shutting down.")
+	return true
+}
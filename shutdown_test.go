@@ -0,0 +1,144 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Shutdown_success(t *testing.T) {
+	m := &mux.Mux{ShutdownRetryAfter: 1500 * time.Millisecond}
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		fmt.Fprint(w, "slow")
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/slow", slow); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", newTestHandler("orders")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/ready", newTestHandler("ready"), mux.Readiness()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/live", newTestHandler("live"), mux.ShutdownExempt()); err != nil {
+		t.Fatal(err)
+	}
+
+	//Start a request before draining...
+	slowRR := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		m.ServeHTTP(slowRR, httptest.NewRequest(http.MethodGet, "http://localhost/slow", nil))
+		close(served)
+	}()
+	<-started
+
+	//...and start draining, that must wait for it.
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- m.Shutdown(context.Background())
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if want, got := context.DeadlineExceeded, m.Shutdown(ctx); want != got {
+		t.Fatalf("want=%v, got=%v", want, got)
+	}
+
+	tests := []struct {
+		url, want string
+	}{
+		{"http://localhost/orders", "503 2 close"},
+		{"http://localhost/ready", "503  "},
+		{"http://localhost/live", "200  live"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Retry-After"), " ", rr.Header().Get("Connection"))
+		if rr.Code == http.StatusOK {
+			got += rr.Body.String()
+		}
+		if test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+
+	close(release)
+	<-served
+	if err := <-shutdown; err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "slow", slowRR.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Shutdown_successIdle(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders", newTestHandler("orders")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/orders", nil))
+	if want, got := "503 1", fmt.Sprint(rr.Code, " ", rr.Header().Get("Retry-After")); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	routes := m.Routes()
+	if want, got := "false false", fmt.Sprint(routes[0].ShutdownExempt, " ", routes[0].Readiness); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Shutdown_successExemptNotWaited(t *testing.T) {
+	m := &mux.Mux{}
+	started, release := make(chan struct{}), make(chan struct{})
+	events := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/events", events, mux.ShutdownExempt()); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/events", nil))
+		close(done)
+	}()
+	<-started
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	//The exempt stream is still blocked, but Shutdown must not wait for it.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+)
+
+//Errors returned by the MaxResponseSize option.
+var (
+	//ErrMaxResponseSizeMustBeValid is returned by Handle method when the MaxResponseSize option is not positive.
+	ErrMaxResponseSizeMustBeValid = errors.New("mux: max response size must be positive")
+	//ErrResponseTooLarge is returned by the response writer Write method of routes with the MaxResponseSize option, when the limit is exceeded.
+	ErrResponseTooLarge = errors.New("mux: response too large")
+)
+
+//MaxResponseSize caps the response body bytes written by the route handler, so bandwidth-heavy endpoints cannot exceed a budget.
+//
+//Writes over the limit are truncated and fail with mux.ErrResponseTooLarge. As the response is incomplete, it is aborted (with http.ErrAbortHandler) when the handler returns,
+//so clients do not take it as complete.
+//
+//Errors
+//
+//• mux.ErrMaxResponseSizeMustBeValid
+func MaxResponseSize(max int64) RouteOption {
+	return func(o *routeOptions) error {
+		if max <= 0 {
+			return ErrMaxResponseSizeMustBeValid
+		}
+		o.maxResponseSize = max
+		return nil
+	}
+}
+
+//limitResponseSize creates a handler that limits the response body bytes written by the next one.
+func limitResponseSize(max int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w, max: max}
+		next.ServeHTTP(rw, r)
+		if rw.exceeded {
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
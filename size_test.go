@@ -0,0 +1,90 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_AuditBytes_success(t *testing.T) {
+	m := &mux.Mux{AuditBytes: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/report", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, strings.NewReader("0123456789"))
+	})); err != nil {
+		t.Fatal(err)
+	}
+	var bytes int64
+	m.Observer = observerFunc(func(obs mux.Observation) { bytes = obs.BytesWritten })
+	for i := 0; i < 3; i++ {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/report", nil))
+	}
+	if want, got := "30 10", fmt.Sprint(m.Stats()[0].BytesWritten, " ", bytes); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_MaxResponseSize_success(t *testing.T) {
+	m := &mux.Mux{}
+	var writeErr error
+	if err := m.Handle(http.MethodGet, "http://localhost/report", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte("0123")); err != nil {
+			t.Fatal(err)
+		}
+		_, writeErr = w.Write([]byte("456789"))
+	}), mux.MaxResponseSize(8)); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if want, got := http.ErrAbortHandler, recover(); want != got {
+				t.Fatalf("want=%v, got=%v", want, got)
+			}
+		}()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/report", nil))
+	}()
+	if want, got := "01234567", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	if writeErr != mux.ErrResponseTooLarge {
+		t.Fatalf("want=%v, got=%v", mux.ErrResponseTooLarge, writeErr)
+	}
+}
+
+func TestMux_Handle_failMaxResponseSize(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.MaxResponseSize(0)); err != mux.ErrMaxResponseSizeMustBeValid {
+		t.Fatal("expected: mux.ErrMaxResponseSizeMustBeValid")
+	}
+}
+
+//observerFunc is an Observer reporting only the observations.
+type observerFunc func(mux.Observation)
+
+func (f observerFunc) Begin(r *http.Request, route mux.RouteInfo) *http.Request {
+	return r
+}
+
+func (f observerFunc) End(r *http.Request, route mux.RouteInfo, obs mux.Observation) {
+	f(obs)
+}
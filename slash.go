@@ -0,0 +1,95 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+//RedirectSlash makes the trailing slash of the route URL pattern canonical: requests to the other slash variant are redirected to it.
+//
+//Eg: A route registered as http://localhost/docs/ redirects requests to http://localhost/docs , and vice versa. The query string is kept.
+//GET and HEAD requests are redirected with http.StatusMovedPermanently, others with http.StatusPermanentRedirect, so the method and body are preserved.
+//Both variants are already the same route, so no conflicting entry is needed. The root route is never redirected.
+//
+//Set Mux.RedirectSlash to redirect the requests of all routes.
+func RedirectSlash() RouteOption {
+	return func(o *routeOptions) error {
+		o.redirectSlash = true
+		o.keepSlash = false
+		return nil
+	}
+}
+
+//KeepSlash makes the route answer both trailing slash variants without redirects, even when Mux.RedirectSlash is set. It is the default behavior of routes.
+func KeepSlash() RouteOption {
+	return func(o *routeOptions) error {
+		o.keepSlash = true
+		o.redirectSlash = false
+		return nil
+	}
+}
+
+//applySlashPolicy makes a new entry redirect the other slash variant, when Mux.RedirectSlash is set and the route does not keep both.
+//
+//Routes ending with {*} (Eg: created by Mount and HandlePrefix methods) are skipped: the slash of their sub paths belongs to the handler they delegate to,
+//and redirecting it here loops with a sub Mux or an http.FileServer using the other variant.
+func (m *Mux) applySlashPolicy(e *muxEntry) {
+	if !m.RedirectSlash || e.options.redirectSlash || e.options.keepSlash {
+		return
+	}
+	if n := len(e.route.path); n > 0 && e.route.path[n-1] == "{*}" {
+		return
+	}
+	e.options.redirectSlash = true
+	*e = newMuxEntry(e.route, e.handler, e.options)
+}
+
+//redirectSlash creates a handler that redirects requests not using the canonical trailing slash before calling the next one.
+//
+//The Location is relative to the request path (Eg: ./docs/ or ../docs), so it can never become protocol-relative (Eg: //evil.com/),
+//and it keeps working when the request path was stripped by Mount or HandlePrefix methods.
+func redirectSlash(trailingSlash bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.EscapedPath()
+		if strings.Trim(path, "/") == "" || strings.HasSuffix(path, "/") == trailingSlash {
+			next.ServeHTTP(w, r)
+			return
+		}
+		location := relativeSlashLocation(path, trailingSlash)
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		//http.Redirect would make the Location absolute, using the request path.
+		w.Header().Set("Location", location)
+		w.WriteHeader(code)
+	})
+}
+
+//relativeSlashLocation builds the relative reference of the other slash variant of a path, resolved against the path itself.
+//Eg: ./docs/ for /api/docs and ../docs for /api/docs/ (each extra trailing slash needs one more ../).
+func relativeSlashLocation(path string, trailingSlash bool) string {
+	if trailingSlash {
+		return "./" + path[strings.LastIndex(path, "/")+1:] + "/"
+	}
+	trimmed := strings.TrimRight(path, "/")
+	return strings.Repeat("../", len(path)-len(trimmed)) + trimmed[strings.LastIndex(trimmed, "/")+1:]
+}
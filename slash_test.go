@@ -0,0 +1,196 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_RedirectSlash_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/docs/", newTestHandler("docs"), mux.RedirectSlash()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "http://localhost/orders", newTestHandler("orders"), mux.RedirectSlash()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", newTestHandler("root"), mux.RedirectSlash()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, url, want string
+	}{
+		{http.MethodGet, "http://localhost/docs?page=2", "301 ./docs/?page=2"},
+		{http.MethodGet, "http://localhost/docs/", "200 "},
+		{http.MethodPost, "http://localhost/orders/", "308 ../orders"},
+		{http.MethodPost, "http://localhost/orders", "200 "},
+		{http.MethodGet, "http://localhost/", "200 "},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Location")); test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+}
+
+func TestMux_RedirectSlash_successMux(t *testing.T) {
+	m := &mux.Mux{RedirectSlash: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/docs/", newTestHandler("docs")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/files", newTestHandler("files"), mux.KeepSlash()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.HandleAll([]mux.RouteSpec{{Method: http.MethodPut, URLPattern: "http://localhost/orders", Handler: newTestHandler("orders")}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, url, want string
+	}{
+		{http.MethodGet, "http://localhost/docs", "301 ./docs/"},
+		{http.MethodGet, "http://localhost/files/", "200 "},
+		{http.MethodGet, "http://localhost/files", "200 "},
+		{http.MethodPut, "http://localhost/orders/", "308 ../orders"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.url, nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Location")); test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+}
+
+func TestMux_RedirectSlash_successRelativeLocation(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/{a}/", newTestHandler("a"), mux.RedirectSlash()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/{a}/{b}", newTestHandler("b"), mux.RedirectSlash()); err != nil {
+		t.Fatal(err)
+	}
+
+	//The Location resolves to the same host, even for protocol-relative looking paths.
+	tests := []struct {
+		url, want string
+	}{
+		{"http://localhost//evil.com", "http://localhost//evil.com/"},
+		{"http://localhost///evil.com", "http://localhost///evil.com/"},
+		{"http://localhost/docs", "http://localhost/docs/"},
+		{"http://localhost/docs/v1/", "http://localhost/docs/v1"},
+		{"http://localhost/docs/v1///", "http://localhost/docs/v1"},
+		{"http://localhost//evil.com/x/", "http://localhost//evil.com/x"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		location := rr.Header().Get("Location")
+		if strings.HasPrefix(location, "/") {
+			t.Fatalf("url=%q, want a relative path, got=%q", test.url, location)
+		}
+		base, err := url.Parse(test.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := url.Parse(location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := base.ResolveReference(ref).String(); test.want != got {
+			t.Fatalf("url=%q, location=%q, want=%q, got=%q", test.url, location, test.want, got)
+		}
+	}
+}
+
+func TestMux_RedirectSlash_successMount(t *testing.T) {
+	m, api := &mux.Mux{}, &mux.Mux{RedirectSlash: true}
+	if err := api.Handle(http.MethodGet, "http://localhost/users/", newTestHandler("users")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Mount("http://localhost/api/{*}", api); err != nil {
+		t.Fatal(err)
+	}
+
+	//The sub Mux only sees the stripped path, but the redirect must stay below the mount prefix.
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/api/users?page=2", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "301 ./users/?page=2", fmt.Sprint(rr.Code, " ", rr.Header().Get("Location")); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	ref, err := url.Parse(rr.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "http://localhost/api/users/?page=2", req.URL.ResolveReference(ref).String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_RedirectSlash_successMuxMount(t *testing.T) {
+	m, api := &mux.Mux{RedirectSlash: true}, &mux.Mux{RedirectSlash: true}
+	if err := api.Handle(http.MethodGet, "http://localhost/users/", newTestHandler("users")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Mount("http://localhost/api/{*}", api); err != nil {
+		t.Fatal(err)
+	}
+
+	//The mount entry leaves the slash to the sub Mux, otherwise both would redirect to each other.
+	for _, test := range []struct{ url, want string }{
+		{"http://localhost/api/users/", "200 "},
+		{"http://localhost/api/users", "301 ./users/"},
+	} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Location")); test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+}
+
+func TestMux_RedirectSlash_successMuxHandlePrefix(t *testing.T) {
+	m := &mux.Mux{RedirectSlash: true}
+	files := fstest.MapFS{"docs/index.html": &fstest.MapFile{Data: []byte("docs")}}
+	if err := m.HandlePrefix(http.MethodGet, "http://localhost/static/{*}", http.FileServer(http.FS(files))); err != nil {
+		t.Fatal(err)
+	}
+
+	//The http.FileServer redirects directories to their trailing slash variant, so the Mux must not redirect them back.
+	for _, test := range []struct{ url, want string }{
+		{"http://localhost/static/docs/", "200 "},
+		{"http://localhost/static/docs", "301 docs/"},
+	} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if got := fmt.Sprint(rr.Code, " ", rr.Header().Get("Location")); test.want != got {
+			t.Fatalf("url=%q, want=%q, got=%q", test.url, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//Errors returned by Syncer methods.
+var (
+	//ErrRouteSourceMustBeValid is returned by Syncer methods when the Mux or the Source is nil, or when the Source lists routes with empty or duplicated keys.
+	ErrRouteSourceMustBeValid = errors.New("mux: invalid route source")
+)
+
+//defaultSyncRetryInterval is the time waited after a failed synchronization when Syncer.RetryInterval is zero.
+const defaultSyncRetryInterval = 5 * time.Second
+
+//SourceRoute is a route kept in an external store (Eg: etcd or Consul), listed by a RouteSource.
+type SourceRoute struct {
+	//Key identifies the route in the store. Eg: the etcd key.
+	Key string
+	//Revision changes whenever the route changes in the store. Eg: the etcd ModRevision or the Consul ModifyIndex.
+	Revision string
+	//RouteSpec holds the route built from the stored configuration. Eg: a mux.Proxy handler to the configured backends.
+	RouteSpec
+}
+
+//RouteSource is an external store of routes, so fleets of instances share the same routing table.
+type RouteSource interface {
+	//List returns all the routes currently in the store.
+	List(ctx context.Context) ([]SourceRoute, error)
+	//Watch blocks until the routes in the store change, or the context is done.
+	Watch(ctx context.Context) error
+}
+
+//Syncer keeps the routes of a Mux in lockstep with a RouteSource.
+//Eg: go (&mux.Syncer{Mux: m, Source: etcdSource}).Run(ctx) .
+//
+//Only the routes registered by the Syncer are touched. Routes registered by other means are kept.
+type Syncer struct {
+	//Mux receives the routes.
+	Mux *Mux
+	//Source provides the routes.
+	Source RouteSource
+	//RetryInterval is the time waited after a failed synchronization, before trying again. If zero, 5 seconds are waited.
+	RetryInterval time.Duration
+	//OnError, if not nil, is called by Run method with the errors of failed synchronizations.
+	OnError func(err error)
+	lock    sync.Mutex
+	routes  map[string]SourceRoute
+}
+
+//Sync lists the routes of the Source once and applies the differences to the Mux.
+//
+//Routes removed from the Source are removed from the Mux, changed routes (with a new Revision) are replaced and the new routes are registered, all in a single atomic swap.
+//If the new routes cannot be registered, the Mux is left untouched (the removed and changed routes are still served), and the changes are tried again by the next synchronization.
+//
+//Errors
+//
+//• mux.ErrRouteSourceMustBeValid
+//
+//• Any error returned by Source List method.
+//
+//• Any error returned by Mux HandleAll and RemoveHandler methods, except mux.ErrRouteMustExist.
+func (s *Syncer) Sync(ctx context.Context) error {
+	listed, err := s.list(ctx)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stale, added := s.diff(listed)
+
+	//Swap the routes deleted or changed in the Source by the new ones at once, so a failure keeps serving the current routes.
+	if err := s.Mux.replaceAll(sourceRouteSpecs(stale), sourceRouteSpecs(added)); err != nil {
+		return err
+	}
+	if s.routes == nil {
+		s.routes = map[string]SourceRoute{}
+	}
+	for _, sr := range stale {
+		delete(s.routes, sr.Key)
+	}
+	for _, sr := range added {
+		s.routes[sr.Key] = sr
+	}
+	return nil
+}
+
+//Plan is a dry run of Sync method: it lists the routes of the Source and returns the changes Sync would apply to the Mux, including all the conflicts, without applying them.
+//
+//Errors
+//
+//• mux.ErrRouteSourceMustBeValid
+//
+//• Any error returned by Source List method.
+//
+//• Any error returned by Mux PlanAll method.
+func (s *Syncer) Plan(ctx context.Context) (UpdatePlan, error) {
+	listed, err := s.list(ctx)
+	if err != nil {
+		return UpdatePlan{}, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stale, added := s.diff(listed)
+	return s.Mux.plan(sourceRouteSpecs(added), sourceRouteSpecs(stale))
+}
+
+//list validates the Syncer and lists the routes of the Source.
+func (s *Syncer) list(ctx context.Context) ([]SourceRoute, error) {
+	if s.Mux == nil || s.Source == nil {
+		return nil, ErrRouteSourceMustBeValid
+	}
+	listed, err := s.Source.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(listed))
+	for _, sr := range listed {
+		if sr.Key == "" || keys[sr.Key] {
+			return nil, ErrRouteSourceMustBeValid
+		}
+		keys[sr.Key] = true
+	}
+	return listed, nil
+}
+
+//diff compares the listed routes with the routes registered by the Syncer. Stale routes were deleted or changed in the Source, and added routes are new or changed. The caller must hold lock.
+func (s *Syncer) diff(listed []SourceRoute) (stale []SourceRoute, added []SourceRoute) {
+	next := make(map[string]SourceRoute, len(listed))
+	for _, sr := range listed {
+		next[sr.Key] = sr
+	}
+	for key, current := range s.routes {
+		if sr, found := next[key]; !found || sr.Revision != current.Revision {
+			stale = append(stale, current)
+		}
+	}
+	for _, sr := range listed {
+		if current, found := s.routes[sr.Key]; !found || sr.Revision != current.Revision {
+			added = append(added, sr)
+		}
+	}
+	return stale, added
+}
+
+//sourceRouteSpecs returns the RouteSpec of each SourceRoute.
+func sourceRouteSpecs(routes []SourceRoute) []RouteSpec {
+	specs := make([]RouteSpec, len(routes))
+	for i, sr := range routes {
+		specs[i] = sr.RouteSpec
+	}
+	return specs
+}
+
+//Run synchronizes the Mux with the Source whenever the Source changes, until the context is done. Failed synchronizations are retried after RetryInterval.
+//
+//Errors
+//
+//• mux.ErrRouteSourceMustBeValid
+//
+//• The context error, when it is done.
+func (s *Syncer) Run(ctx context.Context) error {
+	if s.Mux == nil || s.Source == nil {
+		return ErrRouteSourceMustBeValid
+	}
+	retry := s.RetryInterval
+	if retry <= 0 {
+		retry = defaultSyncRetryInterval
+	}
+	for {
+		err := s.Sync(ctx)
+		if err == nil {
+			err = s.Source.Watch(ctx)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			continue
+		}
+		if s.OnError != nil {
+			s.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
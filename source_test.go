@@ -0,0 +1,211 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+//testSource is an in memory RouteSource.
+type testSource struct {
+	lock    sync.Mutex
+	routes  []mux.SourceRoute
+	changed chan struct{}
+	err     error
+}
+
+func (s *testSource) List(ctx context.Context) ([]mux.SourceRoute, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]mux.SourceRoute(nil), s.routes...), s.err
+}
+
+func (s *testSource) Watch(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.changed:
+		return nil
+	}
+}
+
+func (s *testSource) set(routes ...mux.SourceRoute) {
+	s.lock.Lock()
+	s.routes = routes
+	s.lock.Unlock()
+}
+
+func textHandler(text string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(text))
+	})
+}
+
+func sourceRoute(key, revision, urlPattern, text string) mux.SourceRoute {
+	return mux.SourceRoute{Key: key, Revision: revision, RouteSpec: mux.RouteSpec{Method: http.MethodGet, URLPattern: urlPattern, Handler: textHandler(text)}}
+}
+
+func serveText(m *mux.Mux, url string) string {
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	return w.Body.String()
+}
+
+func TestSyncer_Sync_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/static", textHandler("static")); err != nil {
+		t.Fatal(err)
+	}
+	source := &testSource{}
+	s := &mux.Syncer{Mux: m, Source: source}
+
+	source.set(sourceRoute("a", "1", "http://localhost/a", "a1"), sourceRoute("b", "1", "http://localhost/b", "b1"))
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if serveText(m, "http://localhost/a") != "a1" || serveText(m, "http://localhost/b") != "b1" {
+		t.Fatal("expected: the source routes")
+	}
+
+	source.set(sourceRoute("a", "2", "http://localhost/a", "a2"), sourceRoute("c", "1", "http://localhost/c", "c1"))
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := serveText(m, "http://localhost/a"); got != "a2" {
+		t.Fatalf("expected: a2, got: %q", got)
+	}
+	if serveText(m, "http://localhost/c") != "c1" || serveText(m, "http://localhost/static") != "static" {
+		t.Fatal("expected: the new and the static routes")
+	}
+	if _, found := m.Match(httptest.NewRequest(http.MethodGet, "http://localhost/b", nil)); found {
+		t.Fatal("expected: the removed route not found")
+	}
+}
+
+func TestSyncer_Sync_conflictRetried(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/a", textHandler("static")); err != nil {
+		t.Fatal(err)
+	}
+	source := &testSource{}
+	s := &mux.Syncer{Mux: m, Source: source}
+	source.set(sourceRoute("a", "1", "http://localhost/a", "a1"), sourceRoute("b", "1", "http://localhost/b", "b1"))
+	if err := s.Sync(context.Background()); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatalf("expected: mux.ErrRouteMustNotConflict, got: %v", err)
+	}
+	if _, found := m.Match(httptest.NewRequest(http.MethodGet, "http://localhost/b", nil)); found {
+		t.Fatal("expected: no route registered")
+	}
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if serveText(m, "http://localhost/a") != "a1" {
+		t.Fatal("expected: the source route")
+	}
+}
+
+func TestSyncer_Sync_conflictKeepsCurrentRoutes(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/static", textHandler("static")); err != nil {
+		t.Fatal(err)
+	}
+	source := &testSource{}
+	s := &mux.Syncer{Mux: m, Source: source}
+	source.set(sourceRoute("a", "1", "http://localhost/a", "a1"), sourceRoute("b", "1", "http://localhost/b", "b1"))
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	//The changed route a and the removed route b must still be served when the new route c conflicts.
+	source.set(sourceRoute("a", "2", "http://localhost/a", "a2"), sourceRoute("c", "1", "http://localhost/static", "c1"))
+	if err := s.Sync(context.Background()); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatalf("expected: mux.ErrRouteMustNotConflict, got: %v", err)
+	}
+	if got := serveText(m, "http://localhost/a") + " " + serveText(m, "http://localhost/b") + " " + serveText(m, "http://localhost/static"); got != "a1 b1 static" {
+		t.Fatalf("expected: the current routes, got: %q", got)
+	}
+
+	//The next synchronization applies all the changes.
+	if err := m.RemoveHandler(http.MethodGet, "http://localhost/static"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := serveText(m, "http://localhost/a") + " " + serveText(m, "http://localhost/static"); got != "a2 c1" {
+		t.Fatalf("expected: the source routes, got: %q", got)
+	}
+	if _, found := m.Match(httptest.NewRequest(http.MethodGet, "http://localhost/b", nil)); found {
+		t.Fatal("expected: the removed route not found")
+	}
+}
+
+func TestSyncer_Sync_failRouteSourceMustBeValid(t *testing.T) {
+	if err := (&mux.Syncer{}).Sync(context.Background()); err != mux.ErrRouteSourceMustBeValid {
+		t.Fatalf("expected: mux.ErrRouteSourceMustBeValid, got: %v", err)
+	}
+	source := &testSource{}
+	source.set(sourceRoute("a", "1", "http://localhost/a", "a"), sourceRoute("a", "1", "http://localhost/b", "b"))
+	if err := (&mux.Syncer{Mux: &mux.Mux{}, Source: source}).Sync(context.Background()); err != mux.ErrRouteSourceMustBeValid {
+		t.Fatalf("expected: mux.ErrRouteSourceMustBeValid, got: %v", err)
+	}
+}
+
+func TestSyncer_Run_success(t *testing.T) {
+	m := &mux.Mux{}
+	source := &testSource{changed: make(chan struct{})}
+	source.err = errors.New("unavailable")
+	errs := make(chan error, 10)
+	s := &mux.Syncer{Mux: m, Source: source, RetryInterval: time.Millisecond, OnError: func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	if err := <-errs; err.Error() != "unavailable" {
+		t.Fatalf("expected: unavailable, got: %v", err)
+	}
+
+	source.lock.Lock()
+	source.err = nil
+	source.routes = []mux.SourceRoute{sourceRoute("a", "1", "http://localhost/a", "a1")}
+	source.lock.Unlock()
+	source.changed <- struct{}{}
+	deadline := time.Now().Add(time.Second)
+	for serveText(m, "http://localhost/a") != "a1" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected: the source route")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected: context.Canceled, got: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"net/http"
+)
+
+const (
+	//Used in request contexts.
+	ctxStateValue = "gitlab.com/gopherburrow/mux State"
+)
+
+//The key used to store the requestState of a request dispatched by a Mux.
+var ctxState = ctxType(ctxStateValue)
+
+//requestState holds what a Mux knows about a request dispatched to a route, stored under a single context key.
+//So the request scoped APIs (Eg: Get, PathVars, QueryVars and CurrentRoute) share a single context value, instead of one per API.
+//
+//It is never reused by other requests, so requests kept by goroutines outliving the handler still describe their own route.
+type requestState struct {
+	mux   *Mux
+	entry muxEntry
+	//path is the escaped request path matched by the entry, and host is the request host.
+	//They are kept, so path variables are extracted right even if middleware rewrites the request URL. Eg: http.StripPrefix.
+	path string
+	host string
+	//parent is the state of the Mux that dispatched the request into this one (See Mount method).
+	parent *requestState
+}
+
+//newRequestState creates the requestState of a request dispatched to an entry.
+func newRequestState(m *Mux, r *http.Request, e muxEntry) *requestState {
+	s := &requestState{mux: m, entry: e, path: r.URL.EscapedPath(), host: r.Host}
+	s.parent, _ = stateOf(r)
+	return s
+}
+
+//stateOf retrieves the requestState of a request dispatched by a Mux.
+func stateOf(r *http.Request) (*requestState, bool) {
+	s, ok := r.Context().Value(ctxState).(*requestState)
+	return s, ok
+}
+
+//stateFor finds the requestState of a request dispatched by the Mux, also when it was dispatched into another Mux afterwards (See Mount method).
+func stateFor(m *Mux, r *http.Request) (*requestState, bool) {
+	s, ok := stateOf(r)
+	for ok && s.mux != m {
+		s, ok = s.parent, s.parent != nil
+	}
+	return s, ok
+}
+
+//matchedEntry finds the entry of a request, its escaped path and its canonical host (only needed by routes with a host variable).
+//Requests dispatched by the Mux use their requestState. Other requests (Eg: dispatched by another Mux) are searched in the routing table.
+func (m *Mux) matchedEntry(r *http.Request) (e muxEntry, path, host string, found bool) {
+	if s, ok := stateFor(m, r); ok {
+		if s.entry.route.hostVar != "" {
+			m.entriesLock.RLock()
+			host = m.routingHost(s.host)
+			m.entriesLock.RUnlock()
+		}
+		return s.entry, s.path, host, true
+	}
+	scheme, reqSegs := m.requestScheme(r), m.requestPathSegs(r)
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	host = m.routingHost(r.Host)
+	i, _, found := m.searchPath(scheme, host, reqSegs)
+	if !found {
+		return muxEntry{}, "", "", false
+	}
+	return m.entries[i], r.URL.EscapedPath(), host, true
+}
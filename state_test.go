@@ -0,0 +1,69 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_PathVars_successRewrittenURL(t *testing.T) {
+	m := &mux.Mux{}
+	var got string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, err := mux.CurrentRoute(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = fmt.Sprint(route, " ", m.PathVars(r), " ", m.PathValues(r), " ", r.URL.Path)
+	})
+	if err := m.Handle(http.MethodGet, "http://{tenant}.example.com/api/users/{id}", http.StripPrefix("/api", handler)); err != nil {
+		t.Fatal(err)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://acme.example.com/api/users/42", nil))
+	if want := "GET+http://{tenant}.example.com/api/users/{id} map[id:42 tenant:acme] [42] /users/42"; want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_ServeHTTP_successRequestStateRetained(t *testing.T) {
+	m := &mux.Mux{}
+	retained := map[string]*http.Request{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		retained[m.PathVars(r)["name"]] = r
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/files/{name}", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/other/{name}", handler); err != nil {
+		t.Fatal(err)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/files/report", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/other/other", nil))
+
+	//A request kept after its handler returns still describes its own route.
+	r := retained["report"]
+	route, err := mux.CurrentRoute(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "GET+http://localhost/files/{name} map[name:report]", fmt.Sprint(route, " ", m.PathVars(r)); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
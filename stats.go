@@ -0,0 +1,104 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+//routeStats holds the counters of a routing table entry.
+type routeStats struct {
+	hits         uint64
+	panics       uint64
+	serverErrors uint64
+	disconnects  uint64
+	bytesWritten uint64
+}
+
+//countErrors counts a recovered panic, a 5xx response or a client disconnect of a dispatched request. It must be deferred, so the panic is recovered (and then re-panicked).
+//
+//The client context is the request context before the Mux added values and deadlines, so it is only canceled by the server when the client goes away.
+func (s *routeStats) countErrors(rw *responseWriter, client context.Context) {
+	if p := recover(); p != nil {
+		//http.ErrAbortHandler aborts responses on purpose (Eg: by FaultInjector and MaxResponseSize), so it is not a handler failure.
+		if p != http.ErrAbortHandler {
+			atomic.AddUint64(&s.panics, 1)
+		}
+		panic(p)
+	}
+	if client.Err() == context.Canceled {
+		atomic.AddUint64(&s.disconnects, 1)
+	}
+	if rw.statusCode() >= 500 {
+		atomic.AddUint64(&s.serverErrors, 1)
+	}
+}
+
+//countBytes counts the response body bytes of a dispatched request.
+func (s *routeStats) countBytes(rw *responseWriter) {
+	atomic.AddUint64(&s.bytesWritten, uint64(rw.written))
+}
+
+//RouteStats holds the counters of a route.
+type RouteStats struct {
+	//Route is the route in the format method+scheme://host:port/path/...?query1=value&...
+	Route string `json:"route"`
+	//Owner is the module or team name set by mux.Owner option.
+	Owner string `json:"owner,omitempty"`
+	//Hits is the number of dispatched requests, counted when Mux.AuditHits is set.
+	Hits uint64 `json:"hits"`
+	//Panics is the number of handler panics, except with `http.ErrAbortHandler`, counted when Mux.AuditErrors is set. Panics are re-panicked after being counted, so the server still handles them.
+	Panics uint64 `json:"panics"`
+	//ServerErrors is the number of 5xx responses, counted when Mux.AuditErrors is set.
+	ServerErrors uint64 `json:"serverErrors"`
+	//Disconnects is the number of requests whose client went away (the request context was canceled) before the handler returned,
+	//counted when Mux.AuditErrors is set. Routes with many disconnects are too slow for real users, even when they eventually succeed.
+	Disconnects uint64 `json:"disconnects"`
+	//BytesWritten is the number of response body bytes written, counted when Mux.AuditBytes is set. Bandwidth-heavy routes can be capped by mux.MaxResponseSize option.
+	BytesWritten uint64 `json:"bytesWritten"`
+}
+
+//Stats returns the counters of all registered routes, in routing table order, so unstable endpoints are visible without external tools.
+func (m *Mux) Stats() []RouteStats {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	stats := make([]RouteStats, len(m.entries))
+	for i, e := range m.entries {
+		stats[i] = RouteStats{
+			Route:        e.route.String(),
+			Owner:        e.options.owner,
+			Hits:         atomic.LoadUint64(&e.stats.hits),
+			Panics:       atomic.LoadUint64(&e.stats.panics),
+			ServerErrors: atomic.LoadUint64(&e.stats.serverErrors),
+			Disconnects:  atomic.LoadUint64(&e.stats.disconnects),
+			BytesWritten: atomic.LoadUint64(&e.stats.bytesWritten),
+		}
+	}
+	return stats
+}
+
+//ServeStats registers a GET debug route serving the counters returned by Stats method as a JSON document.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+func (m *Mux) ServeStats(urlPattern string, opts ...RouteOption) error {
+	handler := m.serveJSON(func() ([]byte, error) {
+		return json.Marshal(m.Stats())
+	})
+	return m.Handle(http.MethodGet, urlPattern, handler, opts...)
+}
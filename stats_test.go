@@ -0,0 +1,100 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Stats_success(t *testing.T) {
+	m := &mux.Mux{AuditHits: true, AuditErrors: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/unstable", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("panic") != "" {
+			panic("boom")
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ServeStats("http://localhost/debug/stats"); err != nil {
+		t.Fatal(err)
+	}
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/unstable", nil))
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Fatalf("want=%q, got=%v", "boom", p)
+			}
+		}()
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/unstable?panic=1", nil))
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/debug/stats", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	want := `[{"route":"GET+http://localhost/debug/stats","hits":1,"panics":0,"serverErrors":0,"disconnects":0,"bytesWritten":0},{"route":"GET+http://localhost/unstable","hits":2,"panics":1,"serverErrors":1,"disconnects":0,"bytesWritten":0}]`
+	if got := rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Stats_successDisconnects(t *testing.T) {
+	m := &mux.Mux{AuditErrors: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("gone") != "" {
+			cancel()
+			<-r.Context().Done()
+		}
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/slow", handler, mux.ContextTimeout(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/slow", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/slow?gone=1", nil).WithContext(ctx))
+	if want, got := "1", fmt.Sprint(m.Stats()[0].Disconnects); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Stats_successAbortIsNotPanic(t *testing.T) {
+	m := &mux.Mux{AuditErrors: true}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/aborted", handler); err != nil {
+		t.Fatal(err)
+	}
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Fatalf("want=%v, got=%v", http.ErrAbortHandler, p)
+			}
+		}()
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/aborted", nil))
+	}()
+	if want, got := "0", fmt.Sprint(m.Stats()[0].Panics); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
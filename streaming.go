@@ -0,0 +1,28 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+//Streaming flags a long-lived route (Eg: Server-Sent Events or long polling), exempting it from the cross-cutting behaviors that would break it.
+//
+//Streaming routes ignore the ContextTimeout option (Eg: inherited from a group) and the Record and ETag options, and the response writers used in dispatch never buffer:
+//they implement `http.Flusher` and `http.Hijacker` when the server writer does.
+//The flag is reported by RouteInfo, so external wrappers (Eg: compression middleware using CurrentRoute function) can exempt the route too.
+func Streaming() RouteOption {
+	return func(o *routeOptions) error {
+		o.streaming = true
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+//Stub is a canned response served by a route registered with Stub method.
+type Stub struct {
+	//Status is the HTTP status code. If zero, http.StatusOK is used.
+	Status int
+	//Header contains the response headers. Its values are templates like Body.
+	Header http.Header
+	//Body is a template where each {var} is replaced by the value of the path variable with the same name. Eg: {"id": "{id}"}
+	Body string
+}
+
+//Stub registers a route that serves a canned response, so the routing table can run as a mock server before backends exist.
+//
+//Check the `mux.Mux.Handle` method for the parameters and errors.
+func (m *Mux) Stub(httpMethod string, urlPattern string, stub Stub, opts ...RouteOption) error {
+	return m.Handle(httpMethod, urlPattern, &stubHandler{stub: stub}, opts...)
+}
+
+//stubHandler serves a Stub.
+type stubHandler struct {
+	stub Stub
+}
+
+//ServeHTTP writes the stub response replacing the path variables in header values and body.
+func (h *stubHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	oldnew := []string{}
+	if m, err := Get(r); err == nil {
+		for k, v := range m.PathVars(r) {
+			oldnew = append(oldnew, "{"+k+"}", v)
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	for k, vs := range h.stub.Header {
+		for _, v := range vs {
+			w.Header().Add(k, replacer.Replace(v))
+		}
+	}
+	status := h.stub.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	io.WriteString(w, replacer.Replace(h.stub.Body))
+}
@@ -0,0 +1,64 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Stub_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Stub(http.MethodPost, "http://localhost/orders/{id}/items/{*}", mux.Stub{
+		Status: http.StatusCreated,
+		Header: http.Header{"Location": {"/orders/{id}"}},
+		Body:   `{"id": "{id}", "item": "{*}"}`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Stub(http.MethodGet, "http://localhost/health", mux.Stub{Body: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/orders/42/items/a/b", nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := http.StatusCreated, rr.Code; want != got {
+			t.Fatalf("want=%d, got=%d", want, got)
+		}
+		if want, got := "/orders/42", rr.Header().Get("Location"); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+		if want, got := `{"id": "42", "item": "a/b"}`, rr.Body.String(); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+	}
+
+	{
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/health", nil)
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, req)
+		if want, got := http.StatusOK, rr.Code; want != got {
+			t.Fatalf("want=%d, got=%d", want, got)
+		}
+		if want, got := "ok", rr.Body.String(); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	//Used in request contexts.
+	ctxTemplateValue = "gitlab.com/gopherburrow/mux Template"
+)
+
+//Errors returned by HandleTemplate method.
+var (
+	//ErrTemplateValueMustExist is returned by HandleTemplate method when a {{placeholder}} of the template has no value.
+	ErrTemplateValueMustExist = errors.New("mux: template placeholder without value")
+)
+
+//The key used to store the values of the template instance in dispatching.
+var ctxTemplate = ctxType(ctxTemplateValue)
+
+//Template is a set of parametrized routes, registered once and instantiated for several values by HandleTemplate method.
+//
+//URL patterns use {{name}} placeholders, replaced by the values of each instance. Eg: https://api.example.com/{{resource}}/{id} .
+type Template struct {
+	routes []templateRoute
+}
+
+//templateRoute holds the Handle method parameters of a template route.
+type templateRoute struct {
+	method     string
+	urlPattern string
+	handler    http.Handler
+	opts       []RouteOption
+}
+
+//Handle adds a route to the template. The inputs are validated by HandleTemplate method, for each instance. See `mux.Mux.Handle`.
+func (t *Template) Handle(httpMethod string, urlPattern string, handler http.Handler, opts ...RouteOption) {
+	t.routes = append(t.routes, templateRoute{method: httpMethod, urlPattern: urlPattern, handler: handler, opts: opts})
+}
+
+//HandleTemplate registers every route of the template once per instance, replacing the {{name}} placeholders by the instance values.
+//
+//The registration is atomic: if any route is invalid or conflicts (with existing routes or with another instance), no route is registered.
+//
+//The values of the instance that matched a request can be retrieved in handlers using TemplateValue function.
+//
+//Errors
+//
+//• mux.ErrTemplateValueMustExist
+//
+//• Any error returned by Handle method.
+func (m *Mux) HandleTemplate(t *Template, instances ...map[string]string) error {
+	//Validate every instance before touching the routing table.
+	newEntries := make([]muxEntry, 0, len(t.routes)*len(instances))
+	for _, values := range instances {
+		instance := make(map[string]string, len(values))
+		oldnew := make([]string, 0, len(values)*2)
+		for name, value := range values {
+			instance[name] = value
+			oldnew = append(oldnew, "{{"+name+"}}", value)
+		}
+		replacer := strings.NewReplacer(oldnew...)
+		for _, tr := range t.routes {
+			urlPattern := replacer.Replace(tr.urlPattern)
+			if strings.Contains(urlPattern, "{{") {
+				return ErrTemplateValueMustExist
+			}
+			opts := append([]RouteOption{ContextValue(ctxTemplate, instance)}, tr.opts...)
+			e, err := newHandleEntry(tr.method, urlPattern, tr.handler, opts)
+			if err != nil {
+				return err
+			}
+			newEntries = append(newEntries, e)
+		}
+	}
+
+	return m.insertAll(newEntries)
+}
+
+//TemplateValue retrieves the value of a placeholder of the template instance that matched the request. It returns "" if the route was not registered by HandleTemplate.
+func TemplateValue(r *http.Request, name string) string {
+	values, _ := r.Context().Value(ctxTemplate).(map[string]string)
+	return values[name]
+}
@@ -0,0 +1,67 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_HandleTemplate_success(t *testing.T) {
+	m := &mux.Mux{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Method, " ", mux.TemplateValue(r, "resource"))
+	})
+	tmpl := &mux.Template{}
+	tmpl.Handle(http.MethodGet, "http://localhost/{{resource}}", handler)
+	tmpl.Handle(http.MethodPost, "http://localhost/{{resource}}", handler)
+	if err := m.HandleTemplate(tmpl, map[string]string{"resource": "widgets"}, map[string]string{"resource": "gadgets"}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 4, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/gadgets", nil)
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, req)
+	if want, got := "POST gadgets", rr.Body.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_HandleTemplate_failRollback(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodPost, "http://localhost/gadgets", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &mux.Template{}
+	tmpl.Handle(http.MethodGet, "http://localhost/{{resource}}", http.HandlerFunc(emptyHandler))
+	tmpl.Handle(http.MethodPost, "http://localhost/{{resource}}", http.HandlerFunc(emptyHandler))
+	if err := m.HandleTemplate(tmpl, map[string]string{"resource": "widgets"}, map[string]string{"resource": "gadgets"}); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+	if err := m.HandleTemplate(tmpl, map[string]string{"name": "widgets"}); err != mux.ErrTemplateValueMustExist {
+		t.Fatal("expected: mux.ErrTemplateValueMustExist")
+	}
+	if want, got := 1, len(m.Routes()); want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
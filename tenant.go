@@ -0,0 +1,327 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	//Used in request contexts.
+	ctxTenantValue = "gitlab.com/gopherburrow/mux Tenant"
+)
+
+//Errors returned by TenantMux methods.
+var (
+	//ErrTenantHostPatternMustBeValid is returned by TenantMux methods when the HostPattern field does not contain exactly one variable label. Eg: {tenant}.example.com .
+	ErrTenantHostPatternMustBeValid = errors.New("mux: invalid tenant host pattern")
+	//ErrTenantNameMustBeValid is returned by TenantMux methods when the tenant parameter is empty, is not lowercase or cannot be used as a host label.
+	ErrTenantNameMustBeValid = errors.New("mux: invalid tenant name")
+	//ErrTenantMustExist is returned by TenantMux methods when the tenant is not found.
+	ErrTenantMustExist = errors.New("mux: tenant not found")
+	//ErrTenantMustNotExist is returned by AddTenant method when the tenant already exists.
+	ErrTenantMustNotExist = errors.New("mux: tenant already exists")
+	//ErrTenantsMustNotExceedLimit is returned by AddTenant method when MaxTenants is reached.
+	ErrTenantsMustNotExceedLimit = errors.New("mux: tenants limit exceeded")
+	//ErrTenantRoutesMustNotExceedLimit is returned by TenantMux Handle method when MaxRoutesPerTenant is reached.
+	ErrTenantRoutesMustNotExceedLimit = errors.New("mux: tenant routes limit exceeded")
+	//ErrTenantHostPatternMustNotBePublicSuffix is returned by TenantMux methods when the static part after the variable label of HostPattern is empty or a public suffix. Eg: {tenant}.com .
+	ErrTenantHostPatternMustNotBePublicSuffix = errors.New("mux: tenant host pattern must not be a catch-all on a public suffix")
+	//ErrTenantHostMustMatch is returned by TenantMux Handle method when the urlPattern host does not belong to the tenant.
+	ErrTenantHostMustMatch = errors.New("mux: URL pattern host does not belong to the tenant")
+)
+
+//The key used to store the tenant name selected in dispatching.
+var ctxTenant = ctxType(ctxTenantValue)
+
+//TenantMux implements a multitenant dispatcher that keeps an isolated Mux (routing table) per tenant.
+//
+//The tenant is selected by matching the request host against HostPattern, so a request to acme.example.com
+//only searches the routes of the "acme" tenant, instead of one giant table shared by every tenant.
+type TenantMux struct {
+	//HostPattern is a host, with an optional port, where exactly one label is a variable identifying the tenant.
+	//Eg: "{tenant}.example.com" or "{tenant}.example.com:8080". It must be set before tenants are added.
+	HostPattern string
+	//PublicSuffix reports if a domain is a public suffix, under which unrelated parties register names (Eg: "com" or "co.uk").
+	//It can be backed by the Public Suffix List (Eg: golang.org/x/net/publicsuffix). If nil, only top level domains (a single label) are public suffixes.
+	PublicSuffix func(domain string) bool
+	//AllowPublicSuffix disables the public suffix safety check of HostPattern. Eg: for a gateway that really serves every name under a suffix.
+	AllowPublicSuffix bool
+	//MaxTenants limits the number of tenants. Zero means unlimited.
+	MaxTenants int
+	//MaxRoutesPerTenant limits the number of routes of each tenant. Zero means unlimited.
+	MaxRoutesPerTenant int
+	//NotFoundHandler specifies an optional `http.Handler` when the request host does not select an existing tenant.
+	//If nil, the TenantMux will use the default http.NotFound handler.
+	NotFoundHandler http.Handler
+	tenantsLock     sync.RWMutex
+	tenants         map[string]*Mux
+	//prefix and suffix are the lowercase static parts of HostPattern, parsed by AddTenant method, so ServeHTTP method does not parse it per request.
+	prefix, suffix string
+}
+
+//Tenant retrieves the tenant name selected by a TenantMux in dispatch.
+//
+//Possible error returns:
+//
+//• mux.ErrRequestMustHaveContext
+func Tenant(r *http.Request) (string, error) {
+	t, ok := r.Context().Value(ctxTenant).(string)
+	if !ok {
+		return "", ErrRequestMustHaveContext
+	}
+	return t, nil
+}
+
+//AddTenant creates an empty routing table for a tenant and returns its Mux.
+//
+//Errors
+//
+//• mux.ErrTenantHostPatternMustBeValid
+//
+//• mux.ErrTenantHostPatternMustNotBePublicSuffix
+//
+//• mux.ErrTenantNameMustBeValid
+//
+//• mux.ErrTenantMustNotExist
+//
+//• mux.ErrTenantsMustNotExceedLimit
+func (tm *TenantMux) AddTenant(tenant string) (*Mux, error) {
+	prefix, suffix, err := tm.splitHostPattern()
+	if err != nil {
+		return nil, err
+	}
+	if !validTenantName(tenant) {
+		return nil, ErrTenantNameMustBeValid
+	}
+
+	tm.tenantsLock.Lock()
+	defer tm.tenantsLock.Unlock()
+	tm.prefix, tm.suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	if _, found := tm.tenants[tenant]; found {
+		return nil, ErrTenantMustNotExist
+	}
+	if tm.MaxTenants > 0 && len(tm.tenants) >= tm.MaxTenants {
+		return nil, ErrTenantsMustNotExceedLimit
+	}
+	if tm.tenants == nil {
+		tm.tenants = map[string]*Mux{}
+	}
+	m := &Mux{NotFoundHandler: tm.NotFoundHandler}
+	tm.tenants[tenant] = m
+	return m, nil
+}
+
+//RemoveTenant removes a tenant and its entire routing table.
+//
+//Errors
+//
+//• mux.ErrTenantMustExist
+func (tm *TenantMux) RemoveTenant(tenant string) error {
+	tm.tenantsLock.Lock()
+	defer tm.tenantsLock.Unlock()
+	if _, found := tm.tenants[tenant]; !found {
+		return ErrTenantMustExist
+	}
+	delete(tm.tenants, tenant)
+	return nil
+}
+
+//Tenant returns the Mux of a tenant.
+//
+//Errors
+//
+//• mux.ErrTenantMustExist
+func (tm *TenantMux) Tenant(tenant string) (*Mux, error) {
+	tm.tenantsLock.RLock()
+	defer tm.tenantsLock.RUnlock()
+	m, found := tm.tenants[tenant]
+	if !found {
+		return nil, ErrTenantMustExist
+	}
+	return m, nil
+}
+
+//Tenants returns the sorted names of all tenants.
+func (tm *TenantMux) Tenants() []string {
+	tm.tenantsLock.RLock()
+	defer tm.tenantsLock.RUnlock()
+	tenants := make([]string, 0, len(tm.tenants))
+	for t := range tm.tenants {
+		tenants = append(tenants, t)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+//Handle creates a routing entry in the tenant routing table. See `mux.Mux.Handle`.
+//
+//The urlPattern host must be the HostPattern with the variable label replaced by the tenant name.
+//
+//Errors
+//
+//• mux.ErrTenantHostPatternMustBeValid
+//
+//• mux.ErrTenantHostPatternMustNotBePublicSuffix
+//
+//• mux.ErrTenantMustExist
+//
+//• mux.ErrTenantHostMustMatch
+//
+//• mux.ErrTenantRoutesMustNotExceedLimit
+//
+//• Any error returned by `mux.Mux.Handle`.
+func (tm *TenantMux) Handle(tenant, httpMethod, urlPattern string, handler http.Handler, opts ...RouteOption) error {
+	prefix, suffix, err := tm.splitHostPattern()
+	if err != nil {
+		return err
+	}
+
+	//The write lock guarantees that the routes limit is not exceeded by concurrent calls.
+	tm.tenantsLock.Lock()
+	defer tm.tenantsLock.Unlock()
+	m, found := tm.tenants[tenant]
+	if !found {
+		return ErrTenantMustExist
+	}
+	if u, err := url.Parse(urlPattern); err == nil && u.Host != prefix+tenant+suffix {
+		return ErrTenantHostMustMatch
+	}
+	m.entriesLock.RLock()
+	eLen := len(m.entries)
+	m.entriesLock.RUnlock()
+	if tm.MaxRoutesPerTenant > 0 && eLen >= tm.MaxRoutesPerTenant {
+		return ErrTenantRoutesMustNotExceedLimit
+	}
+	return m.Handle(httpMethod, urlPattern, handler, opts...)
+}
+
+//RemoveHandler removes a handler from the tenant routing table. See `mux.Mux.RemoveHandler`.
+//
+//Errors
+//
+//• mux.ErrTenantMustExist
+//
+//• Any error returned by `mux.Mux.RemoveHandler`.
+func (tm *TenantMux) RemoveHandler(tenant, httpMethod, urlPattern string, opts ...RouteOption) error {
+	m, err := tm.Tenant(tenant)
+	if err != nil {
+		return err
+	}
+	return m.RemoveHandler(httpMethod, urlPattern, opts...)
+}
+
+//ServeHTTP selects the tenant using the request host and dispatches the request to its Mux, passing the tenant name in Context.
+//
+//Hosts are compared case-insensitively, and the request port is ignored when HostPattern has no port.
+//Requests with a host not matching HostPattern or selecting an unknown tenant are handled by NotFoundHandler.
+func (tm *TenantMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tm.tenantsLock.RLock()
+	prefix, suffix := tm.prefix, tm.suffix
+	tm.tenantsLock.RUnlock()
+	host := strings.ToLower(r.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil && !strings.Contains(suffix, ":") {
+		host = h
+	}
+	if !strings.HasPrefix(host, prefix) || !strings.HasSuffix(host, suffix) || len(host) <= len(prefix)+len(suffix) {
+		tm.notFound(w, r)
+		return
+	}
+	tenant := host[len(prefix) : len(host)-len(suffix)]
+	if !validTenantName(tenant) {
+		tm.notFound(w, r)
+		return
+	}
+
+	tm.tenantsLock.RLock()
+	m, found := tm.tenants[tenant]
+	tm.tenantsLock.RUnlock()
+	if !found {
+		tm.notFound(w, r)
+		return
+	}
+	m.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxTenant, tenant)))
+}
+
+//notFound calls a handler when a tenant is not found in ServeHTTP method. And if it is not set call the default http.NotFound handler.
+func (tm *TenantMux) notFound(w http.ResponseWriter, r *http.Request) {
+	if tm.NotFoundHandler == nil {
+		http.NotFound(w, r)
+		return
+	}
+	tm.NotFoundHandler.ServeHTTP(w, r)
+}
+
+//splitHostPattern returns the static parts before and after the variable label of HostPattern, validating it.
+//
+//Patterns where the part after the variable label is empty or a public suffix (Eg: {tenant}.com) are rejected, unless AllowPublicSuffix is set,
+//as they would make the gateway a catch-all for unrelated domains.
+func (tm *TenantMux) splitHostPattern() (prefix string, suffix string, err error) {
+	prefix, suffix, err = splitTenantHostPattern(tm.HostPattern)
+	if err != nil || tm.AllowPublicSuffix {
+		return prefix, suffix, err
+	}
+	domain := strings.TrimPrefix(suffix, ".")
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+	if isPublicSuffix(tm.PublicSuffix, domain) {
+		return "", "", ErrTenantHostPatternMustNotBePublicSuffix
+	}
+	return prefix, suffix, nil
+}
+
+//isTopLevelDomain tests if a domain has a single label.
+func isTopLevelDomain(domain string) bool {
+	return !strings.Contains(domain, ".")
+}
+
+//splitTenantHostPattern returns the static parts before and after the variable label of a tenant host pattern.
+func splitTenantHostPattern(hostPattern string) (prefix string, suffix string, err error) {
+	labels := strings.Split(hostPattern, ".")
+	varLabel := -1
+	for i, l := range labels {
+		if !strings.HasPrefix(l, "{") || !strings.HasSuffix(l, "}") {
+			continue
+		}
+		if varLabel != -1 || strings.TrimSpace(strings.Trim(l, "{}")) == "" {
+			return "", "", ErrTenantHostPatternMustBeValid
+		}
+		varLabel = i
+	}
+	if varLabel == -1 || strings.ContainsAny(hostPattern, "/?#@") {
+		return "", "", ErrTenantHostPatternMustBeValid
+	}
+	if varLabel > 0 {
+		prefix = strings.Join(labels[:varLabel], ".") + "."
+	}
+	if varLabel < len(labels)-1 {
+		suffix = "." + strings.Join(labels[varLabel+1:], ".")
+	}
+	return prefix, suffix, nil
+}
+
+//validTenantName tests if a tenant name can be used as a single host label. Names are lowercase, as request hosts are matched lowercased.
+func validTenantName(tenant string) bool {
+	return tenant != "" && !strings.ContainsAny(tenant, ".:/?#@{} ") && tenant == strings.ToLower(tenant)
+}
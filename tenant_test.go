@@ -0,0 +1,208 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestTenantMux_ServeHTTP_success(t *testing.T) {
+	tm := &mux.TenantMux{HostPattern: "{tenant}.example.com"}
+	for _, tenant := range []string{"acme", "globex"} {
+		if _, err := tm.AddTenant(tenant); err != nil {
+			t.Fatal(err)
+		}
+		if err := tm.Handle(tenant, http.MethodGet, "http://"+tenant+".example.com/{path}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, err := mux.Tenant(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mx, err := mux.Get(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprint(w, tenant+" "+mx.PathVars(r)["path"])
+		})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		req := httptest.NewRequest(http.MethodGet, "http://acme.example.com/gopher", nil)
+		rr := httptest.NewRecorder()
+		tm.ServeHTTP(rr, req)
+		if want, got := "acme gopher", rr.Body.String(); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+	}
+
+	{
+		req := httptest.NewRequest(http.MethodGet, "http://globex.example.com/burrow", nil)
+		rr := httptest.NewRecorder()
+		tm.ServeHTTP(rr, req)
+		if want, got := "globex burrow", rr.Body.String(); want != got {
+			t.Fatalf("want=%q, got=%q", want, got)
+		}
+	}
+
+	for _, host := range []string{"initech.example.com", "example.com", "acme.example.org", "a.b.example.com"} {
+		req := httptest.NewRequest(http.MethodGet, "http://"+host+"/gopher", nil)
+		rr := httptest.NewRecorder()
+		tm.ServeHTTP(rr, req)
+		if want, got := http.StatusNotFound, rr.Code; want != got {
+			t.Fatalf("host=%q, want=%d, got=%d", host, want, got)
+		}
+	}
+
+	if want, got := "[acme globex]", fmt.Sprint(tm.Tenants()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	if err := tm.RemoveTenant("acme"); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://acme.example.com/gopher", nil)
+	rr := httptest.NewRecorder()
+	tm.ServeHTTP(rr, req)
+	if want, got := http.StatusNotFound, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestTenantMux_ServeHTTP_successHostNormalized(t *testing.T) {
+	tests := []struct {
+		hostPattern, host, want string
+	}{
+		{"{tenant}.example.com", "ACME.Example.COM", "acme"},
+		{"{tenant}.example.com", "acme.example.com:8443", "acme"},
+		{"{tenant}.example.com:8080", "acme.example.com:8080", "acme"},
+		{"{tenant}.example.com:8080", "acme.example.com:9090", "404 page not found\n"},
+		{"{tenant}.example.com:8080", "acme.example.com", "404 page not found\n"},
+	}
+	for _, test := range tests {
+		tm := &mux.TenantMux{HostPattern: test.hostPattern}
+		m, err := tm.AddTenant("acme")
+		if err != nil {
+			t.Fatal(err)
+		}
+		//The tenant Mux answers every request, so only the tenant selection is tested.
+		m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, _ := mux.Tenant(r)
+			fmt.Fprint(w, tenant)
+		})
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.Host = test.host
+		rr := httptest.NewRecorder()
+		tm.ServeHTTP(rr, req)
+		if got := rr.Body.String(); test.want != got {
+			t.Fatalf("hostPattern=%q, host=%q, want=%q, got=%q", test.hostPattern, test.host, test.want, got)
+		}
+	}
+}
+
+func TestTenantMux_ServeHTTP_successPatternParsedOnce(t *testing.T) {
+	calls := 0
+	tm := &mux.TenantMux{HostPattern: "{tenant}.example.com", PublicSuffix: func(domain string) bool {
+		calls++
+		return false
+	}}
+	if _, err := tm.AddTenant("acme"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		tm.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://acme.example.com/", nil))
+	}
+	if want, got := 1, calls; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestTenantMux_AddTenant_fail(t *testing.T) {
+	{
+		tm := &mux.TenantMux{HostPattern: "example.com"}
+		if _, err := tm.AddTenant("acme"); err != mux.ErrTenantHostPatternMustBeValid {
+			t.Fatal("expected: mux.ErrTenantHostPatternMustBeValid")
+		}
+	}
+
+	{
+		tm := &mux.TenantMux{HostPattern: "{tenant}.{region}.example.com"}
+		if _, err := tm.AddTenant("acme"); err != mux.ErrTenantHostPatternMustBeValid {
+			t.Fatal("expected: mux.ErrTenantHostPatternMustBeValid")
+		}
+	}
+
+	for _, hostPattern := range []string{"{tenant}.com", "{tenant}.com:8080", "{tenant}", "api.{tenant}.co.uk"} {
+		tm := &mux.TenantMux{HostPattern: hostPattern, PublicSuffix: func(domain string) bool {
+			return domain == "com" || domain == "co.uk"
+		}}
+		if _, err := tm.AddTenant("acme"); err != mux.ErrTenantHostPatternMustNotBePublicSuffix {
+			t.Fatalf("hostPattern=%q, expected: mux.ErrTenantHostPatternMustNotBePublicSuffix", hostPattern)
+		}
+		tm.AllowPublicSuffix = true
+		if _, err := tm.AddTenant("acme"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tm := &mux.TenantMux{HostPattern: "{tenant}.example.com", MaxTenants: 1}
+	for _, tenant := range []string{"acme.corp", "Acme"} {
+		if _, err := tm.AddTenant(tenant); err != mux.ErrTenantNameMustBeValid {
+			t.Fatalf("tenant=%q, expected: mux.ErrTenantNameMustBeValid", tenant)
+		}
+	}
+	if _, err := tm.AddTenant("acme"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.AddTenant("acme"); err != mux.ErrTenantMustNotExist {
+		t.Fatal("expected: mux.ErrTenantMustNotExist")
+	}
+	if _, err := tm.AddTenant("globex"); err != mux.ErrTenantsMustNotExceedLimit {
+		t.Fatal("expected: mux.ErrTenantsMustNotExceedLimit")
+	}
+	if err := tm.RemoveTenant("globex"); err != mux.ErrTenantMustExist {
+		t.Fatal("expected: mux.ErrTenantMustExist")
+	}
+}
+
+func TestTenantMux_Handle_fail(t *testing.T) {
+	tm := &mux.TenantMux{HostPattern: "{tenant}.example.com:8080", MaxRoutesPerTenant: 1}
+	if _, err := tm.AddTenant("acme"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.Handle("globex", http.MethodGet, "http://globex.example.com:8080/", http.HandlerFunc(emptyHandler)); err != mux.ErrTenantMustExist {
+		t.Fatal("expected: mux.ErrTenantMustExist")
+	}
+	if err := tm.Handle("acme", http.MethodGet, "http://globex.example.com:8080/", http.HandlerFunc(emptyHandler)); err != mux.ErrTenantHostMustMatch {
+		t.Fatal("expected: mux.ErrTenantHostMustMatch")
+	}
+	if err := tm.Handle("acme", http.MethodGet, "http://acme.example.com:8080/", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.Handle("acme", http.MethodGet, "http://acme.example.com:8080/other", http.HandlerFunc(emptyHandler)); err != mux.ErrTenantRoutesMustNotExceedLimit {
+		t.Fatal("expected: mux.ErrTenantRoutesMustNotExceedLimit")
+	}
+	if err := tm.RemoveHandler("acme", http.MethodGet, "http://acme.example.com:8080/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.Handle("acme", http.MethodGet, "http://acme.example.com:8080/other", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,115 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//Errors returned by the MaxConcurrent and Throttle options.
+var (
+	//ErrMaxConcurrentMustBeValid is returned by Handle method when the MaxConcurrent option is not positive.
+	ErrMaxConcurrentMustBeValid = errors.New("mux: max concurrent requests must be positive")
+	//ErrThrottlingMustBeValid is returned by Handle method when the Throttle option receives a nil Throttling or a Status that is not 429 or 503.
+	ErrThrottlingMustBeValid = errors.New("mux: Throttling status must be 429 or 503")
+)
+
+//Throttling customizes the response of requests rejected by the MaxConcurrent option, so API consumers get actionable and consistent responses.
+type Throttling struct {
+	//Status is the response status code: http.StatusTooManyRequests or http.StatusServiceUnavailable. If zero, http.StatusServiceUnavailable is used.
+	Status int
+	//RetryAfter is sent in the Retry-After header, rounded up to seconds. Zero omits the header.
+	RetryAfter time.Duration
+	//Header contains additional response headers (Eg: Content-Type of Body).
+	Header http.Header
+	//Body is the response body. If nil, the status text is used.
+	Body []byte
+}
+
+//MaxConcurrent limits the number of requests served at the same time by a route. Requests over the limit are rejected with the Throttling response set by the Throttle option.
+//The Queue option makes them wait for a while instead.
+//
+//Each route has its own limit, even when the option is inherited from a group.
+//
+//Errors
+//
+//• mux.ErrMaxConcurrentMustBeValid
+func MaxConcurrent(n int) RouteOption {
+	return func(o *routeOptions) error {
+		if n <= 0 {
+			return ErrMaxConcurrentMustBeValid
+		}
+		o.maxConcurrent = n
+		return nil
+	}
+}
+
+//Throttle customizes the response of requests rejected by the route limits. Without it, a plain text http.StatusServiceUnavailable response is sent.
+//
+//Errors
+//
+//• mux.ErrThrottlingMustBeValid
+func Throttle(t *Throttling) RouteOption {
+	return func(o *routeOptions) error {
+		if t == nil || (t.Status != 0 && t.Status != http.StatusTooManyRequests && t.Status != http.StatusServiceUnavailable) {
+			return ErrThrottlingMustBeValid
+		}
+		o.throttling = t
+		return nil
+	}
+}
+
+//write sends the throttling response. A nil Throttling sends the default response. Without a Body, the response is written by the ErrorRenderer of the Mux.
+func (t *Throttling) write(w http.ResponseWriter, r *http.Request) {
+	if t == nil {
+		t = &Throttling{}
+	}
+	status := t.Status
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	if t.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64((t.RetryAfter+time.Second-1)/time.Second), 10))
+	}
+	for name, values := range t.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	if t.Body == nil {
+		renderError(w, r, status, "Too many requests are being served by the route.")
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(t.Body)
+}
+
+//limitConcurrency creates a handler that rejects requests when max requests are already being served by the next one, or parks them in a queue if q is not nil.
+func limitConcurrency(max int, q *routeQueue, t *Throttling, next http.Handler) http.Handler {
+	l := &concurrencyLimiter{max: max}
+	if q != nil {
+		l.queue = *q
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.acquire(r) {
+			t.write(w, r)
+			return
+		}
+		defer l.release()
+		next.ServeHTTP(w, r)
+	})
+}
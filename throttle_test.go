@@ -0,0 +1,68 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_MaxConcurrent_success(t *testing.T) {
+	m := &mux.Mux{}
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	throttling := &mux.Throttling{
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: 1500 * time.Millisecond,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"error":"busy"}`),
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/reports", handler, mux.MaxConcurrent(1), mux.Throttle(throttling)); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/reports", nil))
+		close(done)
+	}()
+	<-started
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/reports", nil))
+	close(release)
+	<-done
+	if want, got := `429 2 application/json {"error":"busy"}`, fmt.Sprint(rr.Code, " ", rr.Header().Get("Retry-After"), " ", rr.Header().Get("Content-Type"), " ", rr.Body.String()); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Handle_failThrottling(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.MaxConcurrent(0)); err != mux.ErrMaxConcurrentMustBeValid {
+		t.Fatal("expected: mux.ErrMaxConcurrentMustBeValid")
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/", http.HandlerFunc(emptyHandler), mux.Throttle(&mux.Throttling{Status: http.StatusOK})); err != mux.ErrThrottlingMustBeValid {
+		t.Fatal("expected: mux.ErrThrottlingMustBeValid")
+	}
+}
@@ -0,0 +1,104 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"strings"
+)
+
+//ErrRoutesMustComplyWithPolicy is returned (wrapped in a *mux.ValidationError) by Validate method when routes violate the ValidationPolicy.
+var ErrRoutesMustComplyWithPolicy = errors.New("mux: routes violate the validation policy")
+
+//Rules of a ValidationPolicy, reported in Violation.Rule.
+const (
+	RuleNoRootCatchAll = "no-root-catch-all"
+	RuleRequireOwner   = "require-owner"
+	RuleHTTPSOnly      = "https-only"
+)
+
+//ValidationPolicy selects the rules checked by Validate method.
+type ValidationPolicy struct {
+	//NoRootCatchAll forbids routes whose whole path is a sub path variable (Eg: http://localhost/{*}), as they catch every request of the host.
+	NoRootCatchAll bool
+	//RequireOwner requires every route to have an owner, set by mux.Owner option.
+	RequireOwner bool
+	//HTTPSOnly requires every route to be served only through https, so routes with the http scheme or without a scheme (Eg: //localhost/path) are violations.
+	HTTPSOnly bool
+}
+
+//Violation is a route breaking a rule of a ValidationPolicy.
+type Violation struct {
+	//Route is the violating route.
+	Route RouteInfo
+	//Rule is the broken rule. Eg: mux.RuleRequireOwner.
+	Rule string
+}
+
+//String describes the violation. Eg: require-owner: GET+http://localhost/path .
+func (v Violation) String() string {
+	return v.Rule + ": " + v.Route.String()
+}
+
+//ValidationError is returned by Validate method with all the violations of the routing table.
+//
+//It matches mux.ErrRoutesMustComplyWithPolicy using errors.Is.
+type ValidationError struct {
+	//Violations are sorted in routing table order.
+	Violations []Violation
+}
+
+//Error lists the violations.
+func (e *ValidationError) Error() string {
+	b := strings.Builder{}
+	b.WriteString(ErrRoutesMustComplyWithPolicy.Error())
+	for _, v := range e.Violations {
+		b.WriteString("\n")
+		b.WriteString(v.String())
+	}
+	return b.String()
+}
+
+//Unwrap returns mux.ErrRoutesMustComplyWithPolicy.
+func (e *ValidationError) Unwrap() error {
+	return ErrRoutesMustComplyWithPolicy
+}
+
+//Validate checks the routing table against a policy, returning all the violations at once, so it can run at service startup and block the boot.
+//
+//Errors
+//
+//• mux.ErrRoutesMustComplyWithPolicy (Wrapped in a *mux.ValidationError)
+func (m *Mux) Validate(p ValidationPolicy) error {
+	m.entriesLock.RLock()
+	defer m.entriesLock.RUnlock()
+	violations := []Violation{}
+	for _, e := range m.entries {
+		if p.NoRootCatchAll && len(e.route.path) == 1 && e.route.path[0] == "{*}" {
+			violations = append(violations, Violation{Route: newRouteInfo(e), Rule: RuleNoRootCatchAll})
+		}
+		if p.RequireOwner && e.options.owner == "" {
+			violations = append(violations, Violation{Route: newRouteInfo(e), Rule: RuleRequireOwner})
+		}
+		if p.HTTPSOnly && e.route.scheme != "https" {
+			violations = append(violations, Violation{Route: newRouteInfo(e), Rule: RuleHTTPSOnly})
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_Validate_success(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "https://localhost/{*}", http.HandlerFunc(emptyHandler), mux.Owner("web")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/health", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "https://api.localhost/api/{*}", http.HandlerFunc(emptyHandler), mux.Owner("api")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(mux.ValidationPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := m.Validate(mux.ValidationPolicy{NoRootCatchAll: true, RequireOwner: true, HTTPSOnly: true})
+	if !errors.Is(err, mux.ErrRoutesMustComplyWithPolicy) {
+		t.Fatalf("want=%v, got=%v", mux.ErrRoutesMustComplyWithPolicy, err)
+	}
+	want := "mux: routes violate the validation policy\n" +
+		"require-owner: GET+http://localhost/health\n" +
+		"https-only: GET+http://localhost/health\n" +
+		"no-root-catch-all: GET+https://localhost/{*}"
+	if got := err.Error(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	var verr *mux.ValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 3 || verr.Violations[0].Rule != mux.RuleRequireOwner {
+		t.Fatalf("unexpected violations: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//Errors returned when Mux.StrictVarNames is set.
+var (
+	//ErrVarNameMustBeValid is returned by Handle method when Mux.StrictVarNames is set and a variable name of the urlPattern parameter is not an identifier (Wrapped in a *mux.VarNameError).
+	ErrVarNameMustBeValid = errors.New("mux: variable name must be an identifier")
+)
+
+//VarNameError describes a variable name rejected by Mux.StrictVarNames.
+//
+//It matches mux.ErrVarNameMustBeValid using errors.Is.
+type VarNameError struct {
+	//Route is the route being registered.
+	Route string
+	//Name is the variable name as written in the URL pattern. Eg: " id " for { id } .
+	Name string
+}
+
+//Error describes the route and the variable name.
+func (e *VarNameError) Error() string {
+	return fmt.Sprintf("%v: %q in %s", ErrVarNameMustBeValid, e.Name, e.Route)
+}
+
+//Unwrap returns mux.ErrVarNameMustBeValid.
+func (e *VarNameError) Unwrap() error {
+	return ErrVarNameMustBeValid
+}
+
+//patternVarNames extracts the variable names of an URL pattern as written, with their surrounding spaces:
+//the host variable, the path variables without their types (Eg: id for {id:int}) and the query captures. The {*} sub path and the unnamed value constraints are skipped.
+func patternVarNames(urlPattern string) []string {
+	names := []string{}
+	for rest := urlPattern; ; {
+		start := strings.Index(rest, "{")
+		if start == -1 {
+			return names
+		}
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return names
+		}
+		name := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+		if i := strings.LastIndex(name, ":"); i != -1 {
+			name = name[:i]
+		}
+		if name != "" && name != "*" {
+			names = append(names, name)
+		}
+	}
+}
+
+//validVarName tests if a variable name is an identifier: letters, digits and underscores, not starting with a digit.
+func validVarName(name string) bool {
+	for i, c := range name {
+		if !(unicode.IsLetter(c) || c == '_' || (i > 0 && unicode.IsDigit(c))) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+//checkVarNames returns a *VarNameError for the first variable name of the route that is not an identifier.
+func checkVarNames(e muxEntry) error {
+	for _, name := range e.route.varNames {
+		if !validVarName(name) {
+			return &VarNameError{Route: e.route.String(), Name: name}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_StrictVarNames_success(t *testing.T) {
+	m := &mux.Mux{StrictVarNames: true}
+	for _, pattern := range []string{
+		"http://{tenant}.example.com/users/{id:int}/{_name2}/{*}",
+		"http://localhost/orders?page={page}&size={:1-100}&format={:json|xml}",
+		"http://localhost/ünïcode/{ñame}",
+	} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatalf("pattern=%q, err=%v", pattern, err)
+		}
+	}
+}
+
+func TestMux_StrictVarNames_fail(t *testing.T) {
+	tests := []struct {
+		pattern, want string
+	}{
+		{"http://localhost/users/{ id }", `mux: variable name must be an identifier: " id " in GET+http://localhost/users/{ id }`},
+		{"http://localhost/users/{user-id:int}", `mux: variable name must be an identifier: "user-id" in GET+http://localhost/users/{user-id:int}`},
+		{"http://localhost/users/{2fa}", `mux: variable name must be an identifier: "2fa" in GET+http://localhost/users/{2fa}`},
+		{"http://{ tenant}.example.com/", `mux: variable name must be an identifier: " tenant" in GET+http://{tenant}.example.com/`},
+		{"http://localhost/orders?page={pa ge}", `mux: variable name must be an identifier: "pa ge" in GET+http://localhost/orders?page={pa ge}`},
+	}
+	for _, test := range tests {
+		m := &mux.Mux{StrictVarNames: true}
+		err := m.Handle(http.MethodGet, test.pattern, http.HandlerFunc(emptyHandler))
+		var nameErr *mux.VarNameError
+		if !errors.Is(err, mux.ErrVarNameMustBeValid) || !errors.As(err, &nameErr) {
+			t.Fatalf("pattern=%q, expected: mux.ErrVarNameMustBeValid, got=%v", test.pattern, err)
+		}
+		if got := err.Error(); test.want != got {
+			t.Fatalf("want=%q, got=%q", test.want, got)
+		}
+
+		//Without the policy, the names are trimmed.
+		if err := (&mux.Mux{}).Handle(http.MethodGet, test.pattern, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatalf("pattern=%q, err=%v", test.pattern, err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Errors returned by the typed path variable accessors.
+var (
+	//ErrPathVarMustExist is returned by IntVar, UUIDVar and DateVar methods when the matched route has no path variable with the name.
+	ErrPathVarMustExist = errors.New("mux: path variable not found")
+	//ErrPathVarMustBeValid is returned by IntVar, UUIDVar and DateVar methods when the path variable value cannot be converted to the type.
+	ErrPathVarMustBeValid = errors.New("mux: path variable value is not valid for the type")
+)
+
+//dateLayout is the layout of date path variables (RFC 3339 full-date).
+const dateLayout = "2006-01-02"
+
+//uuidRegexp validates UUID path variables, in any case.
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+//pathVarType validates the values of a typed path variable. Eg: {id:int}
+type pathVarType struct {
+	//valid tests a request path segment.
+	valid func(seg string) bool
+	//example is a valid value, used to create example requests.
+	example string
+	//schema is the OpenAPI schema of the type.
+	schema openAPISchema
+	//goType is the Go type of the values, used by ClientSpec method.
+	goType string
+}
+
+//pathVarTypes are the types accepted after the path variable names.
+var pathVarTypes = map[string]pathVarType{
+	"int": {
+		valid: func(seg string) bool {
+			_, err := strconv.ParseInt(seg, 10, 64)
+			return err == nil
+		},
+		example: "1",
+		schema:  openAPISchema{Type: "integer", Format: "int64"},
+		goType:  "int64",
+	},
+	"uuid": {
+		valid:   uuidRegexp.MatchString,
+		example: "00000000-0000-0000-0000-000000000000",
+		schema:  openAPISchema{Type: "string", Format: "uuid"},
+		goType:  "string",
+	},
+	"date": {
+		valid: func(seg string) bool {
+			_, err := time.Parse(dateLayout, seg)
+			return err == nil
+		},
+		example: "2000-01-01",
+		schema:  openAPISchema{Type: "string", Format: "date"},
+		goType:  "time.Time",
+	},
+}
+
+//parsePathVar extracts the name and the type (empty if untyped) of a path variable segment. Eg: {id:int}
+//
+//It returns false if the segment is static.
+func parsePathVar(seg string) (name string, kind string, isVar bool) {
+	if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+		return "", "", false
+	}
+	name = strings.TrimSpace(strings.Trim(seg, "{}"))
+	if i := strings.LastIndex(name, ":"); i != -1 {
+		name, kind = strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+1:])
+	}
+	return name, kind, true
+}
+
+//acceptsPathVarTypes tests if the request path segments of the typed variables of the route are valid.
+func (r *muxRoute) acceptsPathVarTypes(rm *requestMatch) bool {
+	segs := rm.pathSegs()
+	for _, v := range r.vars {
+		if v.kind != "" && !pathVarTypes[v.kind].valid(segs[v.pathPos]) {
+			return false
+		}
+	}
+	return true
+}
+
+//IntVar returns the value of an int path variable (Eg: {id:int}) from a request that was handled by a Mux.
+//
+//Errors
+//
+//• mux.ErrPathVarMustExist
+//
+//• mux.ErrPathVarMustBeValid
+func (m *Mux) IntVar(r *http.Request, name string) (int64, error) {
+	value, ok := m.PathVars(r)[name]
+	if !ok {
+		return 0, ErrPathVarMustExist
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, ErrPathVarMustBeValid
+	}
+	return n, nil
+}
+
+//UUIDVar returns the value of an uuid path variable (Eg: {id:uuid}) from a request that was handled by a Mux, in lowercase.
+//
+//Errors
+//
+//• mux.ErrPathVarMustExist
+//
+//• mux.ErrPathVarMustBeValid
+func (m *Mux) UUIDVar(r *http.Request, name string) (string, error) {
+	value, ok := m.PathVars(r)[name]
+	if !ok {
+		return "", ErrPathVarMustExist
+	}
+	if !uuidRegexp.MatchString(value) {
+		return "", ErrPathVarMustBeValid
+	}
+	return strings.ToLower(value), nil
+}
+
+//DateVar returns the value of a date path variable (Eg: {day:date}, valued like 2006-01-02) from a request that was handled by a Mux, as midnight UTC.
+//
+//Errors
+//
+//• mux.ErrPathVarMustExist
+//
+//• mux.ErrPathVarMustBeValid
+func (m *Mux) DateVar(r *http.Request, name string) (time.Time, error) {
+	value, ok := m.PathVars(r)[name]
+	if !ok {
+		return time.Time{}, ErrPathVarMustExist
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, ErrPathVarMustBeValid
+	}
+	return t, nil
+}
@@ -0,0 +1,107 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_TypedPathVars_success(t *testing.T) {
+	m := &mux.Mux{}
+	var got string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err1 := m.IntVar(r, "id")
+		key, err2 := m.UUIDVar(r, "key")
+		day, err3 := m.DateVar(r, "day")
+		got = fmt.Sprint(id, " ", key, " ", day.Format(time.RFC3339), " ", err1, " ", err2, " ", err3)
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/orders/{id:int}/keys/{key:uuid}/{day:date}", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/orders/-42/keys/6BA7B810-9DAD-11D1-80B4-00C04FD430C8/2024-02-29", nil))
+	if want := "-42 6ba7b810-9dad-11d1-80b4-00c04fd430c8 2024-02-29T00:00:00Z <nil> <nil> <nil>"; rr.Code != http.StatusOK || want != got {
+		t.Fatalf("want=%q, got=%d %q", want, rr.Code, got)
+	}
+
+	for _, path := range []string{
+		"/orders/abc/keys/6ba7b810-9dad-11d1-80b4-00c04fd430c8/2024-02-29",
+		"/orders/1/keys/6ba7b810/2024-02-29",
+		"/orders/1/keys/6ba7b810-9dad-11d1-80b4-00c04fd430c8/2023-02-29",
+	} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		if want, got := http.StatusNotFound, rr.Code; want != got {
+			t.Fatalf("%s: want=%d, got=%d", path, want, got)
+		}
+	}
+}
+
+func TestMux_TypedPathVars_failPathVar(t *testing.T) {
+	m := &mux.Mux{}
+	var err1, err2 error
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err1 = m.IntVar(r, "name")
+		_, err2 = m.DateVar(r, "missing")
+	})
+	if err := m.Handle(http.MethodGet, "http://localhost/users/{name}", handler); err != nil {
+		t.Fatal(err)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/users/john", nil))
+	if err1 != mux.ErrPathVarMustBeValid {
+		t.Fatalf("want=%v, got=%v", mux.ErrPathVarMustBeValid, err1)
+	}
+	if err2 != mux.ErrPathVarMustExist {
+		t.Fatalf("want=%v, got=%v", mux.ErrPathVarMustExist, err2)
+	}
+}
+
+func TestMux_Handle_failTypedPathVar(t *testing.T) {
+	m := &mux.Mux{}
+	for _, pattern := range []string{"http://localhost/{id:float}", "http://localhost/{:int}", "http://localhost/{*:int}"} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternInvalidPathVar {
+			t.Fatalf("%s: want=%v, got=%v", pattern, mux.ErrURLPatternInvalidPathVar, err)
+		}
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/{id:int}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/{name}", http.HandlerFunc(emptyHandler)); err == nil {
+		t.Fatal("expected: mux.ErrRouteMustNotConflict")
+	}
+}
+
+func TestMux_TypedPathVars_successOpenAPI(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.ServeOpenAPI("http://localhost/openapi.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodGet, "http://localhost/orders/{id:int}", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost/openapi.json", nil))
+	if want, got := `"/orders/{id}":{"get":{"servers":[{"url":"http://localhost"}],"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"integer","format":"int64"}}]`, rr.Body.String(); !strings.Contains(got, want) {
+		t.Fatalf("want=%s, got=%s", want, got)
+	}
+}
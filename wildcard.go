@@ -0,0 +1,181 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+
+package mux
+
+import (
+	"errors"
+	"strings"
+)
+
+//Errors returned by wildcard hosts.
+var (
+	//ErrWildcardHostMustNotBePublicSuffix is returned by Handle method when the domain of a wildcard host (Eg: *.co.uk or {tenant}.co.uk) is a public suffix,
+	//as reported by Mux.PublicSuffix, unless Mux.AllowPublicSuffix is set.
+	ErrWildcardHostMustNotBePublicSuffix = errors.New("mux: wildcard host must not be a catch-all on a public suffix")
+)
+
+const (
+	//wildcardHostPrefix starts the hosts of routes serving all the subdomains of a domain. Eg: *.example.com
+	wildcardHostPrefix = "*."
+	//wildcardPort ends the hosts of routes serving any port. Eg: localhost:*
+	wildcardPort = ":*"
+)
+
+//splitHostPort splits a host in its name and its port, including the colon. The port is empty if the host has none.
+func splitHostPort(host string) (name string, port string) {
+	i := strings.LastIndexByte(host, ':')
+	//The colons of IP literals are not port separators. Eg: [::1]
+	if i == -1 || strings.LastIndexByte(host, ']') > i {
+		return host, ""
+	}
+	return host[:i], host[i:]
+}
+
+//validWildcardHost tests if the wildcards of a route host are valid: "*" is only valid as the whole port or as the whole leftmost label,
+//followed by a domain of at least two labels, so a single route cannot catch every name under a top level domain. Eg: *.com
+func validWildcardHost(host string) bool {
+	name, port := splitHostPort(host)
+	if strings.Contains(port, "*") && port != wildcardPort {
+		return false
+	}
+	if !strings.Contains(name, "*") {
+		return true
+	}
+	domain := strings.TrimPrefix(name, wildcardHostPrefix)
+	if domain == name || strings.Contains(domain, "*") {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if l == "" {
+			return false
+		}
+	}
+	return true
+}
+
+//checkWildcardHost tests if the domain of a wildcard route host is not a public suffix, as TenantMux checks its HostPattern.
+func (m *Mux) checkWildcardHost(route *muxRoute) error {
+	if m.AllowPublicSuffix || !strings.HasPrefix(route.host, wildcardHostPrefix) {
+		return nil
+	}
+	name, _ := splitHostPort(route.host)
+	if isPublicSuffix(m.PublicSuffix, strings.TrimPrefix(name, wildcardHostPrefix)) {
+		return ErrWildcardHostMustNotBePublicSuffix
+	}
+	return nil
+}
+
+//isPublicSuffix tests if a domain is empty or a public suffix, using the publicSuffix function, or isTopLevelDomain when it is nil.
+func isPublicSuffix(publicSuffix func(domain string) bool, domain string) bool {
+	if publicSuffix == nil {
+		publicSuffix = isTopLevelDomain
+	}
+	return domain == "" || publicSuffix(domain)
+}
+
+//authorityStart returns the position of the host of an URL pattern, with a scheme (Eg: http://localhost) or without it (Eg: //localhost). It returns -1 if there is none.
+func authorityStart(urlPattern string) int {
+	if strings.HasPrefix(urlPattern, "//") {
+		return len("//")
+	}
+	i := strings.Index(urlPattern, "://")
+	if i == -1 {
+		return -1
+	}
+	return i + len("://")
+}
+
+//parseWildcardPort removes the wildcard port of an URL pattern (Eg: http://localhost:*/path), as it is not a valid URL port.
+func parseWildcardPort(urlPattern string) (pattern string, anyPort bool) {
+	start := authorityStart(urlPattern)
+	if start == -1 {
+		return urlPattern, false
+	}
+	end := len(urlPattern)
+	if i := strings.IndexAny(urlPattern[start:], "/?#"); i != -1 {
+		end = start + i
+	}
+	if !strings.HasSuffix(urlPattern[:end], wildcardPort) {
+		return urlPattern, false
+	}
+	return urlPattern[:end-len(wildcardPort)] + urlPattern[end:], true
+}
+
+//hostVarValue extracts the value of a host variable from a request host matched by a wildcard route host. Eg: acme for acme.example.com:8080 and *.example.com:* .
+func hostVarValue(host, routeHost string) string {
+	name, _ := splitHostPort(host)
+	routeName, _ := splitHostPort(routeHost)
+	return strings.TrimSuffix(name, strings.TrimPrefix(routeName, "*"))
+}
+
+//parseHostVar replaces a variable leftmost host label of an URL pattern (Eg: https://{tenant}.example.com/app) by "*", returning the variable name.
+//Other URL patterns are returned unchanged, with an empty name.
+func parseHostVar(urlPattern string) (wildcardPattern string, name string, err error) {
+	start := authorityStart(urlPattern)
+	if start == -1 || !strings.HasPrefix(urlPattern[start:], "{") {
+		return urlPattern, "", nil
+	}
+	end := strings.Index(urlPattern[start:], "}")
+	if end == -1 {
+		return "", "", ErrURLPatternMustBeValid
+	}
+	end += start
+	name = strings.TrimSpace(urlPattern[start+1 : end])
+	if name == "" || name == "*" || strings.ContainsAny(name, "{:/") {
+		return "", "", ErrURLPatternInvalidPathVar
+	}
+	return urlPattern[:start] + "*" + urlPattern[end+1:], name, nil
+}
+
+//wildcardHosts returns the wildcard hosts that may serve a request host, from the most to the least specific:
+//the host with any port, then each wildcard subdomain with the port and with any port.
+//Eg: a.b.example.com:8080 returns a.b.example.com:* , *.b.example.com:8080 , *.b.example.com:* , *.example.com:8080 and *.example.com:* .
+//
+//If portless is set, the hosts without port follow the ones with any port. Eg: a.b.example.com , *.b.example.com and *.example.com .
+func wildcardHosts(host string, portless bool) []string {
+	name, port := splitHostPort(host)
+	hosts := []string{name + wildcardPort}
+	if portless && port != "" {
+		hosts = append(hosts, name)
+	}
+	//IP literals have no subdomains.
+	if strings.HasPrefix(name, "[") {
+		return hosts
+	}
+	for i := strings.IndexByte(name, '.'); i != -1; i = strings.IndexByte(name, '.') {
+		name = name[i+1:]
+		if !validWildcardHost(wildcardHostPrefix + name) {
+			break
+		}
+		hosts = append(hosts, wildcardHostPrefix+name+port, wildcardHostPrefix+name+wildcardPort)
+		if portless && port != "" {
+			hosts = append(hosts, wildcardHostPrefix+name)
+		}
+	}
+	return hosts
+}
+
+//exampleHost returns a request host matched by a route host, filling its wildcards. Eg: example.example.com for *.example.com:* .
+func exampleHost(host string) string {
+	if strings.HasPrefix(host, wildcardHostPrefix) {
+		host = "example." + strings.TrimPrefix(host, wildcardHostPrefix)
+	}
+	return strings.TrimSuffix(host, wildcardPort)
+}
@@ -0,0 +1,266 @@
+// This file is part of Gopher Burrow Mux.
+//
+// Gopher Burrow Mux is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher Burrow Mux is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+
+// You should have received a copy of the GNU Lesser General Public License
+// along with Gopher Burrow Mux.  If not, see <http://www.gnu.org/licenses/>.
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/gopherburrow/mux"
+)
+
+func TestMux_WildcardHost_success(t *testing.T) {
+	m := &mux.Mux{MatchCacheSize: 10}
+	handle := func(urlPattern, body string) {
+		if err := m.Handle(http.MethodGet, urlPattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handle("https://*.example.com/path", "wildcard")
+	handle("https://*.eu.example.com/path", "eu")
+	handle("https://admin.example.com/path", "admin")
+	handle("https://*.example.com:8443/path", "port")
+
+	for _, tc := range []struct{ url, want string }{
+		{"https://acme.example.com/path", "wildcard"},
+		{"https://ACME.Example.com/path", "wildcard"},
+		{"https://a.b.example.com/path", "wildcard"},
+		{"https://acme.eu.example.com/path", "eu"},
+		{"https://admin.example.com/path", "admin"},
+		{"https://acme.example.com:8443/path", "port"},
+	} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+		if got := rr.Body.String(); rr.Code != http.StatusOK || tc.want != got {
+			t.Fatalf("%s: want=%q, got=%d %q", tc.url, tc.want, rr.Code, got)
+		}
+	}
+
+	for _, url := range []string{"https://example.com/path", "https://acme.example.org/path", "https://acme.example.com/other"} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		if want, got := http.StatusNotFound, rr.Code; want != got {
+			t.Fatalf("%s: want=%d, got=%d", url, want, got)
+		}
+	}
+}
+
+func TestMux_WildcardHost_successPrecedence(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "https://*.example.com/path", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Handle(http.MethodPost, "https://admin.example.com/path", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+
+	//The request host has a route with the path, so the wildcard host is not searched.
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "https://admin.example.com/path", nil))
+	if want, got := "405 POST", fmt.Sprint(rr.Code, " ", rr.Header().Get("Allow")); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_Handle_failWildcardHost(t *testing.T) {
+	m := &mux.Mux{}
+	for _, pattern := range []string{"https://*.com/", "https://a.*.example.com/", "https://*a.example.com/", "https://*.*.example.com/", "https://*/"} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternMustBeValid {
+			t.Fatalf("%s: want=%v, got=%v", pattern, mux.ErrURLPatternMustBeValid, err)
+		}
+	}
+}
+
+func TestMux_Handle_failWildcardHostPublicSuffix(t *testing.T) {
+	publicSuffix := func(domain string) bool {
+		return domain == "com" || domain == "co.uk"
+	}
+	for _, pattern := range []string{"https://*.co.uk/", "https://*.co.uk:*/", "https://{tenant}.co.uk/"} {
+		m := &mux.Mux{PublicSuffix: publicSuffix}
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != mux.ErrWildcardHostMustNotBePublicSuffix {
+			t.Fatalf("%s: want=%v, got=%v", pattern, mux.ErrWildcardHostMustNotBePublicSuffix, err)
+		}
+		if _, err := m.PlanAll([]mux.RouteSpec{{Method: http.MethodGet, URLPattern: pattern, Handler: http.HandlerFunc(emptyHandler)}}); err != mux.ErrWildcardHostMustNotBePublicSuffix {
+			t.Fatalf("%s: want=%v, got=%v", pattern, mux.ErrWildcardHostMustNotBePublicSuffix, err)
+		}
+		m.AllowPublicSuffix = true
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatalf("%s: %v", pattern, err)
+		}
+	}
+	m := &mux.Mux{PublicSuffix: publicSuffix}
+	for _, pattern := range []string{"https://*.example.co.uk/", "https://www.co.uk/"} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != nil {
+			t.Fatalf("%s: %v", pattern, err)
+		}
+	}
+}
+
+func TestMux_HostVar_success(t *testing.T) {
+	m := &mux.Mux{}
+	var got map[string]string
+	if err := m.Handle(http.MethodGet, "https://{tenant}.example.com/app/{page}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = m.PathVars(r)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "GET+https://{tenant}.example.com/app/{page}", m.Routes()[0].String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "https://Acme.example.com/app/home", nil))
+	if want, got := "200 map[page:home tenant:acme]", fmt.Sprint(rr.Code, " ", got); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	//A host variable route is a wildcard host route.
+	if err := m.Handle(http.MethodGet, "https://*.example.com/app/{name}", http.HandlerFunc(emptyHandler)); !errors.Is(err, mux.ErrRouteMustNotConflict) {
+		t.Fatalf("want=%v, got=%v", mux.ErrRouteMustNotConflict, err)
+	}
+	if err := m.RemoveHandler(http.MethodGet, "https://{tenant}.example.com/app/{page}"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMux_Handle_failHostVar(t *testing.T) {
+	m := &mux.Mux{}
+	for pattern, want := range map[string]error{
+		"https://{}.example.com/":               mux.ErrURLPatternInvalidPathVar,
+		"https://{tenant:int}.example.com/":     mux.ErrURLPatternInvalidPathVar,
+		"https://{tenant}.example.com/{tenant}": mux.ErrURLPatternInvalidPathVar,
+		"https://{tenant}.com/":                 mux.ErrURLPatternMustBeValid,
+		"https://{tenant/":                      mux.ErrURLPatternMustBeValid,
+		"https://a{tenant}.example.com/":        mux.ErrURLPatternMustBeValid,
+	} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != want {
+			t.Fatalf("%s: want=%v, got=%v", pattern, want, err)
+		}
+	}
+}
+
+func TestMux_WildcardPort_success(t *testing.T) {
+	m := &mux.Mux{}
+	handle := func(urlPattern, body string) {
+		if err := m.Handle(http.MethodGet, urlPattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handle("http://localhost:*/{path}", "any")
+	handle("http://localhost:9090/{path}", "9090")
+	handle("http://{tenant}.example.com:*/{path}", "tenant")
+
+	serve := func(url string) string {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return fmt.Sprint(rr.Code, " ", rr.Body.String())
+	}
+	for url, want := range map[string]string{
+		"http://localhost:8080/a":      "200 any",
+		"http://localhost/a":           "200 any",
+		"http://localhost:9090/a":      "200 9090",
+		"http://acme.example.com:81/a": "200 tenant",
+	} {
+		if got := serve(url); want != got {
+			t.Fatalf("%s: want=%q, got=%q", url, want, got)
+		}
+	}
+	if want, got := "GET+http://{tenant}.example.com:*/{path}", m.Routes()[0].String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+
+	var vars map[string]string
+	if err := m.Handle(http.MethodGet, "http://{tenant}.example.org:*/vars/{x}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars = m.PathVars(r)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	serve("http://acme.example.org:8080/vars/1")
+	if want, got := "map[tenant:acme x:1]", fmt.Sprint(vars); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+}
+
+func TestMux_PortInsensitive_success(t *testing.T) {
+	m := &mux.Mux{PortInsensitive: true}
+	if err := m.Handle(http.MethodGet, "http://localhost/path", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost:8080/path", nil))
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+
+	m.PortInsensitive = false
+	rr = httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://localhost:8080/path", nil))
+	if want, got := http.StatusNotFound, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_PortInsensitive_successWildcardHost(t *testing.T) {
+	m := &mux.Mux{PortInsensitive: true}
+	if err := m.Handle(http.MethodGet, "http://*.example.com/x", newTestHandler("x")); err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{"http://a.example.com/x", "http://a.example.com:8080/x", "http://a.b.example.com:8080/x"} {
+		rr := httptest.NewRecorder()
+		m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		if want, got := http.StatusOK, rr.Code; want != got {
+			t.Fatalf("url=%q, want=%d, got=%d", url, want, got)
+		}
+	}
+
+	m.PortInsensitive = false
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://a.example.com:8080/x", nil))
+	if want, got := http.StatusNotFound, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}
+
+func TestMux_Handle_failWildcardPort(t *testing.T) {
+	m := &mux.Mux{}
+	for _, pattern := range []string{"http://localhost:8*/", "http://localhost:80:*/", "http://:*/"} {
+		if err := m.Handle(http.MethodGet, pattern, http.HandlerFunc(emptyHandler)); err != mux.ErrURLPatternMustBeValid {
+			t.Fatalf("%s: want=%v, got=%v", pattern, mux.ErrURLPatternMustBeValid, err)
+		}
+	}
+}
+
+func TestMux_ExampleRequests_successWildcards(t *testing.T) {
+	m := &mux.Mux{}
+	if err := m.Handle(http.MethodGet, "http://{tenant}.example.com:*/app", http.HandlerFunc(emptyHandler)); err != nil {
+		t.Fatal(err)
+	}
+	reqs := m.ExampleRequests()
+	if want, got := "http://example.example.com/app", reqs[0].URL.String(); want != got {
+		t.Fatalf("want=%q, got=%q", want, got)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, reqs[0])
+	if want, got := http.StatusOK, rr.Code; want != got {
+		t.Fatalf("want=%d, got=%d", want, got)
+	}
+}